@@ -0,0 +1,115 @@
+//-----------------------------------------------------------------------------
+// FILE:		backend.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package backend abstracts cadence-proxy away from a single workflow
+// engine SDK.  The proxy can run against either Cadence (go.uber.org/cadence)
+// or Temporal (go.temporal.io/sdk), selected once at startup via SetCurrent.
+// Only the minimum surface the proxy actually touches is wrapped here:
+// workflow.Context, workflow.Channel, workflow.CancelFunc, and the
+// Describe* response types.  Concrete implementations live in the
+// backend/cadence and backend/temporal sub-packages.
+package backend
+
+import "sync/atomic"
+
+// Kind identifies which workflow engine backend cadence-proxy is
+// currently bound to.
+type Kind int32
+
+const (
+
+	// Cadence selects the go.uber.org/cadence backend.  This is the
+	// default and matches cadence-proxy's historical behavior.
+	Cadence Kind = iota
+
+	// Temporal selects the go.temporal.io/sdk backend.
+	Temporal
+)
+
+// String returns the human readable name of a Kind.
+func (k Kind) String() string {
+	switch k {
+	case Temporal:
+		return "temporal"
+	default:
+		return "cadence"
+	}
+}
+
+// current holds the process-wide backend selection.  It is set once at
+// startup (before any Context is created) and read frequently thereafter,
+// so it is stored behind atomic operations rather than a mutex.
+var current int32 = int32(Cadence)
+
+// SetCurrent sets the backend cadence-proxy is bound to.  This is expected
+// to be called once during startup, before any workflow or activity
+// Context is created.
+func SetCurrent(kind Kind) {
+	atomic.StoreInt32(&current, int32(kind))
+}
+
+// Current returns the backend cadence-proxy is currently bound to.
+func Current() Kind {
+	return Kind(atomic.LoadInt32(&current))
+}
+
+type (
+
+	// WorkflowContext abstracts over a backend-specific workflow.Context
+	// (go.uber.org/cadence/workflow.Context or go.temporal.io/sdk/workflow.Context)
+	// so that cadence-proxy's Context types don't need to be compiled
+	// against a single SDK.
+	WorkflowContext interface {
+
+		// Unwrap returns the underlying backend-specific workflow.Context.
+		Unwrap() interface{}
+	}
+
+	// Channel abstracts over a backend-specific workflow.Channel.
+	Channel interface {
+
+		// Unwrap returns the underlying backend-specific workflow.Channel.
+		Unwrap() interface{}
+	}
+
+	// DescribeTaskListResponse abstracts over the backend-specific
+	// response to a describe task list call, so that a DescribeTaskListReply
+	// can carry either one without a compile-time dependency on both SDKs.
+	DescribeTaskListResponse interface {
+
+		// Kind identifies which backend produced this response.
+		Kind() Kind
+
+		// Unwrap returns the underlying backend-specific response.
+		Unwrap() interface{}
+	}
+
+	// DescribeWorkflowExecutionResponse abstracts over the backend-specific
+	// response to a describe workflow execution call.
+	DescribeWorkflowExecutionResponse interface {
+
+		// Kind identifies which backend produced this response.
+		Kind() Kind
+
+		// Unwrap returns the underlying backend-specific response.
+		Unwrap() interface{}
+	}
+)
+
+// CancelFunc abstracts over a backend-specific workflow.CancelFunc.  Both
+// Cadence and Temporal define this as func(), so no wrapper type is needed.
+type CancelFunc func()
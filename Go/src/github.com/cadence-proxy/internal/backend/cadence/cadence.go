@@ -0,0 +1,113 @@
+//-----------------------------------------------------------------------------
+// FILE:		cadence.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cadence implements the backend.Kind Cadence side of the
+// cadence-proxy backend abstraction, wrapping go.uber.org/cadence types.
+package cadence
+
+import (
+	"go.uber.org/cadence/workflow"
+
+	cadenceshared "go.uber.org/cadence/.gen/go/shared"
+
+	"github.com/cadence-proxy/internal/backend"
+)
+
+type (
+
+	// WorkflowContext wraps a go.uber.org/cadence/workflow.Context so that
+	// it satisfies backend.WorkflowContext.
+	WorkflowContext struct {
+		Ctx workflow.Context
+	}
+
+	// Channel wraps a go.uber.org/cadence/workflow.Channel so that it
+	// satisfies backend.Channel.
+	Channel struct {
+		Chan workflow.Channel
+	}
+
+	// DescribeTaskListResponse wraps a cadence DescribeTaskListResponse so
+	// that it satisfies backend.DescribeTaskListResponse.
+	DescribeTaskListResponse struct {
+		Response *cadenceshared.DescribeTaskListResponse
+	}
+
+	// DescribeWorkflowExecutionResponse wraps a cadence
+	// DescribeWorkflowExecutionResponse so that it satisfies
+	// backend.DescribeWorkflowExecutionResponse.
+	DescribeWorkflowExecutionResponse struct {
+		Response *cadenceshared.DescribeWorkflowExecutionResponse
+	}
+)
+
+// NewWorkflowContext wraps a cadence workflow.Context as a backend.WorkflowContext.
+func NewWorkflowContext(ctx workflow.Context) *WorkflowContext {
+	return &WorkflowContext{Ctx: ctx}
+}
+
+// Unwrap returns the underlying cadence workflow.Context.
+func (c *WorkflowContext) Unwrap() interface{} {
+	return c.Ctx
+}
+
+// NewChannel wraps a cadence workflow.Channel as a backend.Channel.
+func NewChannel(ch workflow.Channel) *Channel {
+	return &Channel{Chan: ch}
+}
+
+// Unwrap returns the underlying cadence workflow.Channel.
+func (c *Channel) Unwrap() interface{} {
+	return c.Chan
+}
+
+// WrapCancelFunc adapts a cadence workflow.CancelFunc to a backend.CancelFunc.
+func WrapCancelFunc(fn workflow.CancelFunc) backend.CancelFunc {
+	return backend.CancelFunc(fn)
+}
+
+// NewDescribeTaskListResponse wraps a cadence DescribeTaskListResponse as a
+// backend.DescribeTaskListResponse.
+func NewDescribeTaskListResponse(resp *cadenceshared.DescribeTaskListResponse) *DescribeTaskListResponse {
+	return &DescribeTaskListResponse{Response: resp}
+}
+
+// Kind identifies this response as having come from the Cadence backend.
+func (r *DescribeTaskListResponse) Kind() backend.Kind {
+	return backend.Cadence
+}
+
+// Unwrap returns the underlying *cadenceshared.DescribeTaskListResponse.
+func (r *DescribeTaskListResponse) Unwrap() interface{} {
+	return r.Response
+}
+
+// NewDescribeWorkflowExecutionResponse wraps a cadence
+// DescribeWorkflowExecutionResponse as a backend.DescribeWorkflowExecutionResponse.
+func NewDescribeWorkflowExecutionResponse(resp *cadenceshared.DescribeWorkflowExecutionResponse) *DescribeWorkflowExecutionResponse {
+	return &DescribeWorkflowExecutionResponse{Response: resp}
+}
+
+// Kind identifies this response as having come from the Cadence backend.
+func (r *DescribeWorkflowExecutionResponse) Kind() backend.Kind {
+	return backend.Cadence
+}
+
+// Unwrap returns the underlying *cadenceshared.DescribeWorkflowExecutionResponse.
+func (r *DescribeWorkflowExecutionResponse) Unwrap() interface{} {
+	return r.Response
+}
@@ -0,0 +1,41 @@
+//-----------------------------------------------------------------------------
+// FILE:		errors.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cadence
+
+import "errors"
+
+// Sentinel errors identifying the well-known ways a Cadence activity or
+// workflow fails, for a caller to compare against with errors.Is once
+// messages.RegisterSentinel has associated each one with its
+// messages.ErrorCode (see the messages package's init, which registers
+// these without this package needing to depend on messages itself).
+var (
+
+	// ErrCanceled identifies a canceled activity or workflow.
+	ErrCanceled = errors.New("cadence: activity or workflow was canceled")
+
+	// ErrTimedOut identifies an activity or workflow that exceeded one of
+	// its timeouts.
+	ErrTimedOut = errors.New("cadence: activity or workflow timed out")
+
+	// ErrTerminated identifies a terminated workflow.
+	ErrTerminated = errors.New("cadence: workflow was terminated")
+
+	// ErrPanic identifies an activity or workflow that panicked.
+	ErrPanic = errors.New("cadence: activity or workflow panicked")
+)
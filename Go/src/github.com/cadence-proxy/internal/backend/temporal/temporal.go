@@ -0,0 +1,115 @@
+//-----------------------------------------------------------------------------
+// FILE:		temporal.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package temporal implements the backend.Kind Temporal side of the
+// cadence-proxy backend abstraction, wrapping go.temporal.io/sdk types.
+package temporal
+
+import (
+	"go.temporal.io/sdk/workflow"
+
+	"go.temporal.io/api/workflowservice/v1"
+
+	"github.com/cadence-proxy/internal/backend"
+)
+
+type (
+
+	// WorkflowContext wraps a go.temporal.io/sdk/workflow.Context so that
+	// it satisfies backend.WorkflowContext.
+	WorkflowContext struct {
+		Ctx workflow.Context
+	}
+
+	// Channel wraps a go.temporal.io/sdk/workflow.Channel so that it
+	// satisfies backend.Channel.
+	Channel struct {
+		Chan workflow.ReceiveChannel
+	}
+
+	// DescribeTaskListResponse wraps a temporal DescribeTaskQueueResponse
+	// so that it satisfies backend.DescribeTaskListResponse.  Temporal
+	// renamed "task list" to "task queue", but the field is kept under the
+	// same name here so it lines up with the wire-stable Result property.
+	DescribeTaskListResponse struct {
+		Response *workflowservice.DescribeTaskQueueResponse
+	}
+
+	// DescribeWorkflowExecutionResponse wraps a temporal
+	// DescribeWorkflowExecutionResponse so that it satisfies
+	// backend.DescribeWorkflowExecutionResponse.
+	DescribeWorkflowExecutionResponse struct {
+		Response *workflowservice.DescribeWorkflowExecutionResponse
+	}
+)
+
+// NewWorkflowContext wraps a temporal workflow.Context as a backend.WorkflowContext.
+func NewWorkflowContext(ctx workflow.Context) *WorkflowContext {
+	return &WorkflowContext{Ctx: ctx}
+}
+
+// Unwrap returns the underlying temporal workflow.Context.
+func (c *WorkflowContext) Unwrap() interface{} {
+	return c.Ctx
+}
+
+// NewChannel wraps a temporal workflow.ReceiveChannel as a backend.Channel.
+func NewChannel(ch workflow.ReceiveChannel) *Channel {
+	return &Channel{Chan: ch}
+}
+
+// Unwrap returns the underlying temporal workflow.ReceiveChannel.
+func (c *Channel) Unwrap() interface{} {
+	return c.Chan
+}
+
+// WrapCancelFunc adapts a temporal workflow.CancelFunc to a backend.CancelFunc.
+func WrapCancelFunc(fn workflow.CancelFunc) backend.CancelFunc {
+	return backend.CancelFunc(fn)
+}
+
+// NewDescribeTaskListResponse wraps a temporal DescribeTaskQueueResponse as
+// a backend.DescribeTaskListResponse.
+func NewDescribeTaskListResponse(resp *workflowservice.DescribeTaskQueueResponse) *DescribeTaskListResponse {
+	return &DescribeTaskListResponse{Response: resp}
+}
+
+// Kind identifies this response as having come from the Temporal backend.
+func (r *DescribeTaskListResponse) Kind() backend.Kind {
+	return backend.Temporal
+}
+
+// Unwrap returns the underlying *workflowservice.DescribeTaskQueueResponse.
+func (r *DescribeTaskListResponse) Unwrap() interface{} {
+	return r.Response
+}
+
+// NewDescribeWorkflowExecutionResponse wraps a temporal
+// DescribeWorkflowExecutionResponse as a backend.DescribeWorkflowExecutionResponse.
+func NewDescribeWorkflowExecutionResponse(resp *workflowservice.DescribeWorkflowExecutionResponse) *DescribeWorkflowExecutionResponse {
+	return &DescribeWorkflowExecutionResponse{Response: resp}
+}
+
+// Kind identifies this response as having come from the Temporal backend.
+func (r *DescribeWorkflowExecutionResponse) Kind() backend.Kind {
+	return backend.Temporal
+}
+
+// Unwrap returns the underlying *workflowservice.DescribeWorkflowExecutionResponse.
+func (r *DescribeWorkflowExecutionResponse) Unwrap() interface{} {
+	return r.Response
+}
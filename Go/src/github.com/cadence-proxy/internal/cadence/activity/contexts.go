@@ -19,7 +19,14 @@ package activity
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cadence-proxy/internal/valuebag"
 )
 
 var (
@@ -32,20 +39,31 @@ var (
 
 type (
 
-	// ContextsMap holds a thread-safe map[interface{}]interface{} of
-	// ActivityContexts with their contextID's
+	// ContextsMap holds a thread-safe, sharded map of ActivityContexts
+	// keyed by contextID.  Sharding avoids a single global lock being a
+	// throughput bottleneck when many activities are in flight concurrently;
+	// see contextShard in shard.go.  It also tracks Prometheus-style
+	// counters and, if StartEvictionSweeper is called, evicts entries that
+	// go idle for longer than a configured TTL.
 	ContextsMap struct {
-		sync.Mutex
-		contexts map[int64]*Context
+		shards    []*contextShard
+		shardMask int64
+
+		addedTotal   uint64
+		removedTotal uint64
+		evictedTotal uint64
+		leakTracking int32 // 0 or 1, set via EnableLeakTracking
 	}
 
-	// Context holds a Cadence activity
-	// context, the registered activity function.
+	// Context holds an activity context.Context (populated by either the
+	// Cadence or Temporal backend, selected via backend.Current()) and the
+	// registered activity function name.
 	// This struct is used as an intermediate for storing worklfow information
-	// and state while registering and executing cadence activitys
+	// and state while registering and executing activities
 	Context struct {
 		ctx          context.Context
 		activityName *string
+		values       *valuebag.Bag // structured values (correlation id, span id, ...) attached by proxy middleware
 	}
 )
 
@@ -79,6 +97,7 @@ func GetContextID() int64 {
 // activity ExecutionContext in memory
 func NewActivityContext(ctx context.Context) *Context {
 	actx := new(Context)
+	actx.values = valuebag.New()
 	actx.SetContext(ctx)
 	return actx
 }
@@ -112,14 +131,80 @@ func (actx *Context) SetActivityName(value *string) {
 	actx.activityName = value
 }
 
+// WithValue attaches value under key in the ActivityContext's values bag, so
+// it becomes visible to Value calls made anywhere this Context is Enter'd,
+// independent of the backend context.Context's own value propagation.
+// Typical keys are correlation id, user id, or span id attached by proxy
+// middleware so they automatically show up in every log line emitted while
+// this Context is active.
+func (actx *Context) WithValue(key, value interface{}) {
+	actx.values.WithValue(key, value)
+}
+
+// Value returns the value attached to key in this ActivityContext's own
+// values bag, which is always authoritative for a key it holds.  If this
+// ActivityContext doesn't hold key itself, it falls back to whatever Bag is
+// currently Entered on the calling goroutine, so middleware that attaches a
+// value ambiently (rather than through this specific Context) is still
+// visible.  It returns nil if key isn't set anywhere in either chain.
+func (actx *Context) Value(key interface{}) interface{} {
+	if value, ok := actx.values.Lookup(key); ok {
+		return value
+	}
+
+	if current := valuebag.Current(); current != nil {
+		return current.Value(key)
+	}
+
+	return nil
+}
+
+// Enter makes the ActivityContext's values bag visible to Current() calls on
+// the calling goroutine, and on any other goroutine that calls Enter with
+// the same Bag.  Callers must pair every Enter with a deferred Exit.
+func (actx *Context) Enter() {
+	valuebag.Enter(actx.values)
+}
+
+// Exit pops the ActivityContext's values bag off the calling goroutine's
+// active-Bag stack.  It must be called once for every Enter, typically via
+// defer immediately after Enter.
+func (actx *Context) Exit() {
+	valuebag.Exit()
+}
+
 //----------------------------------------------------------------------------
 // ActivityContextsMap instance methods
 
-// NewActivityContextsMap is the constructor for an ActivityContextsMap
+// NewActivityContextsMap is the constructor for an ActivityContextsMap.
+// It sizes the map to runtime.GOMAXPROCS(0)*4 shards (rounded up to a power
+// of two) so that Add/Remove/Get calls from different activity goroutines
+// don't serialize on a single lock.
 func NewActivityContextsMap() *ContextsMap {
-	o := new(ContextsMap)
-	o.contexts = make(map[int64]*Context)
-	return o
+	return newContextsMapWithShards(defaultShardCount())
+}
+
+// newContextsMapWithShards builds a ContextsMap with the given number of
+// shards, rounded up to the next power of two.  Split out from
+// NewActivityContextsMap so tests can exercise specific shard counts.
+func newContextsMapWithShards(n int) *ContextsMap {
+	n = nextPowerOfTwo(n)
+	shards := make([]*contextShard, n)
+	for i := range shards {
+		shards[i] = &contextShard{contexts: make(map[int64]*contextEntry)}
+	}
+
+	return &ContextsMap{
+		shards:    shards,
+		shardMask: int64(n - 1),
+	}
+}
+
+// shardFor returns the shard responsible for contextID.  contextIDs are a
+// monotonically increasing counter (see NextContextID), so contextID & mask
+// distributes entries evenly across shards.
+func (a *ContextsMap) shardFor(contextID int64) *contextShard {
+	return a.shards[contextID&a.shardMask]
 }
 
 // Add adds a new cadence context and its corresponding ContextId into
@@ -133,9 +218,16 @@ func NewActivityContextsMap() *ContextsMap {
 //
 // returns int64 -> long contextID of the new cadence ActivityContext added to the map
 func (a *ContextsMap) Add(contextID int64, actx *Context) int64 {
-	a.Lock()
-	defer a.Unlock()
-	a.contexts[contextID] = actx
+	entry := newContextEntry(actx)
+	if atomic.LoadInt32(&a.leakTracking) != 0 {
+		entry.stack = debug.Stack()
+	}
+
+	shard := a.shardFor(contextID)
+	shard.Lock()
+	shard.contexts[contextID] = entry
+	shard.Unlock()
+	atomic.AddUint64(&a.addedTotal, 1)
 	return contextID
 }
 
@@ -147,21 +239,221 @@ func (a *ContextsMap) Add(contextID int64, actx *Context) int64 {
 //
 // returns int64 -> long contextID of the ActivityContext removed from the map
 func (a *ContextsMap) Remove(contextID int64) int64 {
-	a.Lock()
-	defer a.Unlock()
-	delete(a.contexts, contextID)
+	shard := a.shardFor(contextID)
+	shard.Lock()
+	_, found := shard.contexts[contextID]
+	delete(shard.contexts, contextID)
+	shard.Unlock()
+	if found {
+		atomic.AddUint64(&a.removedTotal, 1)
+	}
+
 	return contextID
 }
 
 // Get gets a ActivityContext from the ActivityContextsMap at the specified
-// ContextID.  This method is thread-safe.
+// ContextID.  This method is thread-safe and only takes a read lock on the
+// owning shard, so concurrent Get calls for different contextIDs never block
+// each other.
 //
 // param contextID int64 -> the long contextID of activity.
 // This will be the mapped key.
 //
 // returns *ActivityContext -> pointer to ActivityContext with the specified id
 func (a *ContextsMap) Get(contextID int64) *Context {
-	a.Lock()
-	defer a.Unlock()
-	return a.contexts[contextID]
+	shard := a.shardFor(contextID)
+	shard.RLock()
+	entry := shard.contexts[contextID]
+	shard.RUnlock()
+	if entry == nil {
+		return nil
+	}
+
+	return entry.ctx
+}
+
+// Touch resets contextID's idle clock to now, keeping it alive through the
+// next eviction sweep.  Callers should invoke this when a signal, query, or
+// heartbeat arrives for a context that is expected to remain active.  Touch
+// is a no-op if contextID is not present in the map.
+func (a *ContextsMap) Touch(contextID int64) {
+	shard := a.shardFor(contextID)
+	shard.RLock()
+	entry := shard.contexts[contextID]
+	shard.RUnlock()
+	if entry != nil {
+		entry.touch()
+	}
+}
+
+// Len returns the total number of ActivityContexts currently held across
+// all shards.  This method is thread-safe.
+//
+// returns int -> the number of ActivityContexts in the map
+func (a *ContextsMap) Len() int {
+	n := 0
+	for _, shard := range a.shards {
+		shard.RLock()
+		n += len(shard.contexts)
+		shard.RUnlock()
+	}
+
+	return n
+}
+
+// Range calls fn for each contextID/ActivityContext pair held in the map,
+// across all shards, stopping early if fn returns false.  This allows
+// callers (e.g. shutdown/cleanup code) to safely iterate without holding a
+// single lock over the whole map.  The iteration order is not specified
+// and fn must not call back into the ContextsMap it is iterating.
+func (a *ContextsMap) Range(fn func(contextID int64, actx *Context) bool) {
+	for _, shard := range a.shards {
+		shard.RLock()
+		for id, entry := range shard.contexts {
+			if !fn(id, entry.ctx) {
+				shard.RUnlock()
+				return
+			}
+		}
+		shard.RUnlock()
+	}
+}
+
+//----------------------------------------------------------------------------
+// TTL eviction and metrics
+
+// StartEvictionSweeper launches a background goroutine that wakes up every
+// interval and evicts any ActivityContext that has gone idle (not Added or
+// Touched) for at least maxAge, invoking onEvict(contextID, ctx) for each one
+// so the caller can release any resources associated with it.  onEvict is
+// called after the owning shard's lock has been released, so it is safe for
+// onEvict to call back into the map (e.g. Get).  It returns a stop function
+// that halts the sweeper; calling stop more than once is safe, and it is the
+// caller's responsibility to call stop during shutdown.
+func (a *ContextsMap) StartEvictionSweeper(maxAge, interval time.Duration, onEvict func(contextID int64, actx *Context)) (stop func()) {
+	stopCh := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				a.evictIdle(maxAge, onEvict)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stopCh) })
+	}
+}
+
+// evictIdle removes every entry idle for at least maxAge from the map and
+// invokes onEvict for each one once its shard's lock has been released.
+func (a *ContextsMap) evictIdle(maxAge time.Duration, onEvict func(contextID int64, actx *Context)) {
+	now := time.Now()
+	var evicted []evictedContext
+	for _, shard := range a.shards {
+		evicted = evicted[:0]
+		shard.Lock()
+		for id, entry := range shard.contexts {
+			if entry.idleSince(now) >= maxAge {
+				evicted = append(evicted, evictedContext{contextID: id, ctx: entry.ctx})
+				delete(shard.contexts, id)
+			}
+		}
+		shard.Unlock()
+
+		if len(evicted) == 0 {
+			continue
+		}
+
+		atomic.AddUint64(&a.evictedTotal, uint64(len(evicted)))
+		if onEvict == nil {
+			continue
+		}
+
+		for _, e := range evicted {
+			onEvict(e.contextID, e.ctx)
+		}
+	}
+}
+
+// AddedTotal returns the total number of ActivityContexts ever added to the
+// map.  Intended to back a Prometheus counter such as
+// cadence_proxy_activity_contexts_added_total.
+func (a *ContextsMap) AddedTotal() uint64 {
+	return atomic.LoadUint64(&a.addedTotal)
+}
+
+// RemovedTotal returns the total number of ActivityContexts explicitly
+// removed via Remove.  Intended to back a Prometheus counter such as
+// cadence_proxy_activity_contexts_removed_total.
+func (a *ContextsMap) RemovedTotal() uint64 {
+	return atomic.LoadUint64(&a.removedTotal)
+}
+
+// EvictedTotal returns the total number of ActivityContexts evicted by the
+// TTL sweeper started via StartEvictionSweeper.  Intended to back a
+// Prometheus counter such as cadence_proxy_activity_contexts_evicted_total.
+func (a *ContextsMap) EvictedTotal() uint64 {
+	return atomic.LoadUint64(&a.evictedTotal)
+}
+
+// InUse returns the number of ActivityContexts currently held in the map.
+// Intended to back a Prometheus gauge such as
+// cadence_proxy_activity_contexts_in_use; equivalent to Len(), named to
+// match the other counters.
+func (a *ContextsMap) InUse() int {
+	return a.Len()
+}
+
+//----------------------------------------------------------------------------
+// Leak diagnostics
+
+// EnableLeakTracking turns stack-trace capture on (or off) for every Add
+// call made after this point, so DumpLeaks can show where a leaked
+// ActivityContext originated.  It is disabled by default since capturing a
+// stack on every Add has a real cost; enable it only while diagnosing a
+// suspected leak.
+func (a *ContextsMap) EnableLeakTracking(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&a.leakTracking, v)
+}
+
+// DumpLeaks writes a diagnostic report to out listing every ActivityContext
+// that has been alive for at least minAge, along with the stack trace
+// captured at Add time if EnableLeakTracking was on when it was added.
+// Intended to be wired up to an admin endpoint or a periodic diagnostic job
+// so operators can see where a suspected leak originated.
+func (a *ContextsMap) DumpLeaks(out io.Writer, minAge time.Duration) {
+	now := time.Now()
+	for _, shard := range a.shards {
+		shard.RLock()
+		for id, entry := range shard.contexts {
+			age := now.Sub(time.Unix(0, entry.created))
+			if age < minAge {
+				continue
+			}
+
+			name := "<unknown>"
+			if n := entry.ctx.GetActivityName(); n != nil {
+				name = *n
+			}
+
+			fmt.Fprintf(out, "contextID=%d activity=%s age=%s\n", id, name, age)
+			if len(entry.stack) > 0 {
+				out.Write(entry.stack)
+				fmt.Fprintln(out)
+			}
+		}
+		shard.RUnlock()
+	}
 }
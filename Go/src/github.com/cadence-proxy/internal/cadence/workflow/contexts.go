@@ -18,9 +18,15 @@
 package workflow
 
 import (
+	"fmt"
+	"io"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 
-	"go.uber.org/cadence/workflow"
+	"github.com/cadence-proxy/internal/backend"
+	"github.com/cadence-proxy/internal/valuebag"
 )
 
 var (
@@ -33,25 +39,36 @@ var (
 
 type (
 
-	// ContextsMap is a global map of int64 contextID to
-	// running cadence workflow instances (as *WorkflowContext)
+	// ContextsMap is a thread-safe, sharded map of int64 contextID to
+	// running workflow instances (as *WorkflowContext).  Sharding avoids a
+	// single global lock being a throughput bottleneck when many workflow
+	// decisions are in flight concurrently; see contextShard in shard.go.
+	// It also tracks Prometheus-style counters and, if StartEvictionSweeper
+	// is called, evicts entries that go idle for longer than a configured
+	// TTL.
 	ContextsMap struct {
-		sync.Mutex
-		contexts map[int64]*Context
+		shards    []*contextShard
+		shardMask int64
+
+		addedTotal   uint64
+		removedTotal uint64
+		evictedTotal uint64
+		leakTracking int32 // 0 or 1, set via EnableLeakTracking
 	}
 
-	// Context represents a running cadence
+	// Context represents a running cadence or temporal
 	// workflow instance
 	Context struct {
-		sync.Mutex                       // allows us to safely iterate ID iterator
-		workflowName *string             // string name of the workflow
-		ctx          workflow.Context    // the cadence workflow context
-		cancelFunc   workflow.CancelFunc // cadence workflow context cancel function
-		children     *ChildMap           // maps child workflow instances to childID
-		activities   *ActivityMap        // maps activity futures launched by the workflow instance to activityID
-		queues       *QueueMap           // map of workflow queues (queueID to workflow.Channel queue)
-		childID      int64               // childID iterator
-		queueID      int64               // queueID iterator
+		sync.Mutex                          // allows us to safely iterate ID iterator
+		workflowName *string                 // string name of the workflow
+		ctx          backend.WorkflowContext // the backend workflow context
+		cancelFunc   backend.CancelFunc      // backend workflow context cancel function
+		children     *ChildMap               // maps child workflow instances to childID
+		activities   *ActivityMap            // maps activity futures launched by the workflow instance to activityID
+		queues       *QueueMap               // map of workflow queues (queueID to backend.Channel queue)
+		childID      int64                   // childID iterator
+		queueID      int64                   // queueID iterator
+		values       *valuebag.Bag           // structured values (correlation id, span id, ...) attached by proxy middleware
 	}
 )
 
@@ -83,27 +100,39 @@ func GetContextID() int64 {
 //
 // returns *WorkflowContext -> pointer to a newly initialized
 // workflow ExecutionContext in memory
-func NewWorkflowContext(ctx workflow.Context) *Context {
+func NewWorkflowContext(ctx backend.WorkflowContext) *Context {
 	wectx := new(Context)
 	wectx.children = NewChildMap()
 	wectx.activities = NewActivityMap()
 	wectx.queues = NewQueueMap()
+	wectx.values = valuebag.New()
 	wectx.SetContext(ctx)
 	return wectx
 }
 
-// GetContext gets a WorkflowContext's workflow.Context
+// NewChildWorkflowContext creates a Context for a child workflow spawned
+// from parent, inheriting parent's values bag.  Values the child sets via
+// WithValue shadow the parent's without mutating it, so a child workflow can
+// override values like correlation id while still falling through to
+// anything it doesn't set itself.
+func NewChildWorkflowContext(parent *Context, ctx backend.WorkflowContext) *Context {
+	wectx := NewWorkflowContext(ctx)
+	wectx.values = parent.values.NewChild()
+	return wectx
+}
+
+// GetContext gets a WorkflowContext's backend.WorkflowContext
 //
-// returns workflow.Context -> a cadence workflow context
-func (wectx *Context) GetContext() workflow.Context {
+// returns backend.WorkflowContext -> the backend workflow context
+func (wectx *Context) GetContext() backend.WorkflowContext {
 	return wectx.ctx
 }
 
-// SetContext sets a WorkflowContext's workflow.Context
+// SetContext sets a WorkflowContext's backend.WorkflowContext
 //
-// param value workflow.Context -> a cadence workflow context to be
-// set as a WorkflowContext's cadence workflow.Context
-func (wectx *Context) SetContext(value workflow.Context) {
+// param value backend.WorkflowContext -> the backend workflow context to be
+// set as a WorkflowContext's backend.WorkflowContext
+func (wectx *Context) SetContext(value backend.WorkflowContext) {
 	wectx.ctx = value
 }
 
@@ -123,15 +152,15 @@ func (wectx *Context) SetWorkflowName(value *string) {
 
 // GetCancelFunction gets a WorkflowContext's context cancel function
 //
-// returns workflow.CancelFunc -> a cadence workflow context cancel function
-func (wectx *Context) GetCancelFunction() workflow.CancelFunc {
+// returns backend.CancelFunc -> the backend workflow context cancel function
+func (wectx *Context) GetCancelFunction() backend.CancelFunc {
 	return wectx.cancelFunc
 }
 
 // SetCancelFunction sets a WorkflowContext's cancel function
 //
-// param value workflow.CancelFunc -> a cadence workflow context cancel function
-func (wectx *Context) SetCancelFunction(value workflow.CancelFunc) {
+// param value backend.CancelFunc -> the backend workflow context cancel function
+func (wectx *Context) SetCancelFunction(value backend.CancelFunc) {
 	wectx.cancelFunc = value
 }
 
@@ -247,10 +276,10 @@ func (wectx *Context) SetQueues(value *QueueMap) {
 //
 // param id int64 -> the long queueID. This will be the mapped key.
 //
-// param b workflow.Channel -> the workflow.Channel workflow queue. This will be the mapped value.
+// param b backend.Channel -> the backend workflow queue. This will be the mapped value.
 //
 // returns int64 -> long queueID of the newly added queue.
-func (wectx *Context) AddQueue(id int64, b workflow.Channel) int64 {
+func (wectx *Context) AddQueue(id int64, b backend.Channel) int64 {
 	return wectx.queues.Add(id, b)
 }
 
@@ -264,14 +293,14 @@ func (wectx *Context) RemoveQueue(id int64) int64 {
 	return wectx.queues.Remove(id)
 }
 
-// GetQueue gets a workflow.Channel workflow queue from the WorkflowContext's
+// GetQueue gets a backend.Channel workflow queue from the WorkflowContext's
 // QueueMap at the specified queueID. This method is thread-safe.
 //
 // param id int64 -> the long queueID.
 //
-// returns workflow.Channel -> the workflow.Channel workflow queue at the specified
+// returns backend.Channel -> the backend workflow queue at the specified
 // queueID.
-func (wectx *Context) GetQueue(id int64) workflow.Channel {
+func (wectx *Context) GetQueue(id int64) backend.Channel {
 	return wectx.queues.Get(id)
 }
 
@@ -309,14 +338,83 @@ func (wectx *Context) GetQueueID() int64 {
 	return wectx.queueID
 }
 
+// WithValue attaches value under key in the WorkflowContext's values bag, so
+// it becomes visible to Value calls made anywhere this Context is Enter'd,
+// independent of the backend workflow.Context's own value propagation.
+// Typical keys are correlation id, user id, or span id attached by proxy
+// middleware so they automatically show up in every log line emitted while
+// this Context is active.
+func (wectx *Context) WithValue(key, value interface{}) {
+	wectx.values.WithValue(key, value)
+}
+
+// Value returns the value attached to key in this WorkflowContext's own
+// values bag - falling back through a parent workflow's values if this is a
+// child Context created via NewChildWorkflowContext - which is always
+// authoritative for a key it holds.  If this WorkflowContext doesn't hold
+// key itself, it falls back to whatever Bag is currently Entered on the
+// calling goroutine, so middleware that attaches a value ambiently (rather
+// than through this specific Context) is still visible.  It returns nil if
+// key isn't set anywhere in either chain.
+func (wectx *Context) Value(key interface{}) interface{} {
+	if value, ok := wectx.values.Lookup(key); ok {
+		return value
+	}
+
+	if current := valuebag.Current(); current != nil {
+		return current.Value(key)
+	}
+
+	return nil
+}
+
+// Enter makes the WorkflowContext's values bag visible to Current() calls on
+// the calling goroutine, and on any other goroutine that calls Enter with
+// the same Bag - e.g. a goroutine handling an activity launched on behalf of
+// this workflow.  Callers must pair every Enter with a deferred Exit.
+func (wectx *Context) Enter() {
+	valuebag.Enter(wectx.values)
+}
+
+// Exit pops the WorkflowContext's values bag off the calling goroutine's
+// active-Bag stack.  It must be called once for every Enter, typically via
+// defer immediately after Enter.
+func (wectx *Context) Exit() {
+	valuebag.Exit()
+}
+
 //----------------------------------------------------------------------------
 // WorkflowContextsMap instance methods
 
-// NewWorkflowContextsMap is the constructor for an WorkflowContextsMap
+// NewWorkflowContextsMap is the constructor for an WorkflowContextsMap.
+// It sizes the map to runtime.GOMAXPROCS(0)*4 shards (rounded up to a power
+// of two) so that Add/Remove/Get calls from different workflow decision
+// goroutines don't serialize on a single lock.
 func NewWorkflowContextsMap() *ContextsMap {
-	o := new(ContextsMap)
-	o.contexts = make(map[int64]*Context)
-	return o
+	return newContextsMapWithShards(defaultShardCount())
+}
+
+// newContextsMapWithShards builds a ContextsMap with the given number of
+// shards, rounded up to the next power of two.  Split out from
+// NewWorkflowContextsMap so tests can exercise specific shard counts.
+func newContextsMapWithShards(n int) *ContextsMap {
+	n = nextPowerOfTwo(n)
+	shards := make([]*contextShard, n)
+	for i := range shards {
+		shards[i] = &contextShard{contexts: make(map[int64]*contextEntry)}
+	}
+
+	return &ContextsMap{
+		shards:    shards,
+		shardMask: int64(n - 1),
+	}
+}
+
+// shardFor returns the shard responsible for contextID.  contextIDs are a
+// monotonically increasing counter (see NextContextID), so contextID & mask
+// distributes entries evenly across shards.
+func (w *ContextsMap) shardFor(contextID int64) *contextShard {
+	return w.shards[contextID&w.shardMask]
 }
 
 // Add adds a new cadence context and its corresponding ContextId into
@@ -330,9 +428,16 @@ func NewWorkflowContextsMap() *ContextsMap {
 //
 // returns int64 -> long id of the new cadence WorkflowContext added to the map
 func (w *ContextsMap) Add(contextID int64, wectx *Context) int64 {
-	w.Lock()
-	defer w.Unlock()
-	w.contexts[contextID] = wectx
+	entry := newContextEntry(wectx)
+	if atomic.LoadInt32(&w.leakTracking) != 0 {
+		entry.stack = debug.Stack()
+	}
+
+	shard := w.shardFor(contextID)
+	shard.Lock()
+	shard.contexts[contextID] = entry
+	shard.Unlock()
+	atomic.AddUint64(&w.addedTotal, 1)
 	return contextID
 }
 
@@ -344,21 +449,224 @@ func (w *ContextsMap) Add(contextID int64, wectx *Context) int64 {
 //
 // returns int64 -> long id of the WorkflowContext removed from the map
 func (w *ContextsMap) Remove(contextID int64) int64 {
-	w.Lock()
-	defer w.Unlock()
-	delete(w.contexts, contextID)
+	shard := w.shardFor(contextID)
+	shard.Lock()
+	_, found := shard.contexts[contextID]
+	delete(shard.contexts, contextID)
+	shard.Unlock()
+	if found {
+		atomic.AddUint64(&w.removedTotal, 1)
+	}
+
 	return contextID
 }
 
 // Get gets a WorkflowContext from the WorkflowContextsMap at the specified
-// ContextID.  This method is thread-safe.
+// ContextID.  This method is thread-safe and only takes a read lock on the
+// owning shard, so concurrent Get calls for different contextIDs never
+// block each other.
 //
 // param contextID int64 -> the long id contextID of a executing
 // cadence workflow.
 //
 // returns *WorkflowContext -> pointer to WorkflowContext with the specified id
 func (w *ContextsMap) Get(contextID int64) *Context {
-	w.Lock()
-	defer w.Unlock()
-	return w.contexts[contextID]
+	shard := w.shardFor(contextID)
+	shard.RLock()
+	entry := shard.contexts[contextID]
+	shard.RUnlock()
+	if entry == nil {
+		return nil
+	}
+
+	return entry.ctx
+}
+
+// Touch resets contextID's idle clock to now, keeping it alive through the
+// next eviction sweep.  Callers should invoke this when a signal or query
+// arrives for a workflow that is expected to remain active.  Touch is a
+// no-op if contextID is not present in the map.
+func (w *ContextsMap) Touch(contextID int64) {
+	shard := w.shardFor(contextID)
+	shard.RLock()
+	entry := shard.contexts[contextID]
+	shard.RUnlock()
+	if entry != nil {
+		entry.touch()
+	}
+}
+
+// Len returns the total number of WorkflowContexts currently held across
+// all shards.  This method is thread-safe.
+//
+// returns int -> the number of WorkflowContexts in the map
+func (w *ContextsMap) Len() int {
+	n := 0
+	for _, shard := range w.shards {
+		shard.RLock()
+		n += len(shard.contexts)
+		shard.RUnlock()
+	}
+
+	return n
+}
+
+// Range calls fn for each contextID/WorkflowContext pair held in the map,
+// across all shards, stopping early if fn returns false.  This allows
+// callers (e.g. shutdown/cleanup code) to safely iterate without holding a
+// single lock over the whole map.  The iteration order is not specified
+// and fn must not call back into the ContextsMap it is iterating.
+func (w *ContextsMap) Range(fn func(contextID int64, wectx *Context) bool) {
+	for _, shard := range w.shards {
+		shard.RLock()
+		for id, entry := range shard.contexts {
+			if !fn(id, entry.ctx) {
+				shard.RUnlock()
+				return
+			}
+		}
+		shard.RUnlock()
+	}
+}
+
+//----------------------------------------------------------------------------
+// TTL eviction and metrics
+
+// StartEvictionSweeper launches a background goroutine that wakes up every
+// interval and evicts any WorkflowContext that has gone idle (not Added or
+// Touched) for at least maxAge, invoking onEvict(contextID, wectx) for each
+// one.  The onEvict callback is the workflow package's hook for cancelling
+// wectx.GetCancelFunction() and draining/closing any queues left open in
+// wectx.GetQueues(), so a dropped reply or a panicked decision task doesn't
+// leak the WorkflowContext forever.  onEvict is called after the owning
+// shard's lock has been released, so it is safe for onEvict to call back
+// into the map.  It returns a stop function that halts the sweeper; calling
+// stop more than once is safe, and it is the caller's responsibility to call
+// stop during shutdown.
+func (w *ContextsMap) StartEvictionSweeper(maxAge, interval time.Duration, onEvict func(contextID int64, wectx *Context)) (stop func()) {
+	stopCh := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-ticker.C:
+				w.evictIdle(maxAge, onEvict)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(stopCh) })
+	}
+}
+
+// evictIdle removes every entry idle for at least maxAge from the map and
+// invokes onEvict for each one once its shard's lock has been released.
+func (w *ContextsMap) evictIdle(maxAge time.Duration, onEvict func(contextID int64, wectx *Context)) {
+	now := time.Now()
+	var evicted []evictedContext
+	for _, shard := range w.shards {
+		evicted = evicted[:0]
+		shard.Lock()
+		for id, entry := range shard.contexts {
+			if entry.idleSince(now) >= maxAge {
+				evicted = append(evicted, evictedContext{contextID: id, ctx: entry.ctx})
+				delete(shard.contexts, id)
+			}
+		}
+		shard.Unlock()
+
+		if len(evicted) == 0 {
+			continue
+		}
+
+		atomic.AddUint64(&w.evictedTotal, uint64(len(evicted)))
+		if onEvict == nil {
+			continue
+		}
+
+		for _, e := range evicted {
+			onEvict(e.contextID, e.ctx)
+		}
+	}
+}
+
+// AddedTotal returns the total number of WorkflowContexts ever added to the
+// map.  Intended to back a Prometheus counter such as
+// cadence_proxy_workflow_contexts_added_total.
+func (w *ContextsMap) AddedTotal() uint64 {
+	return atomic.LoadUint64(&w.addedTotal)
+}
+
+// RemovedTotal returns the total number of WorkflowContexts explicitly
+// removed via Remove.  Intended to back a Prometheus counter such as
+// cadence_proxy_workflow_contexts_removed_total.
+func (w *ContextsMap) RemovedTotal() uint64 {
+	return atomic.LoadUint64(&w.removedTotal)
+}
+
+// EvictedTotal returns the total number of WorkflowContexts evicted by the
+// TTL sweeper started via StartEvictionSweeper.  Intended to back a
+// Prometheus counter such as cadence_proxy_workflow_contexts_evicted_total.
+func (w *ContextsMap) EvictedTotal() uint64 {
+	return atomic.LoadUint64(&w.evictedTotal)
+}
+
+// InUse returns the number of WorkflowContexts currently held in the map.
+// Intended to back a Prometheus gauge such as
+// cadence_proxy_workflow_contexts_in_use; equivalent to Len(), named to
+// match the other counters.
+func (w *ContextsMap) InUse() int {
+	return w.Len()
+}
+
+//----------------------------------------------------------------------------
+// Leak diagnostics
+
+// EnableLeakTracking turns stack-trace capture on (or off) for every Add
+// call made after this point, so DumpLeaks can show where a leaked
+// WorkflowContext originated.  It is disabled by default since capturing a
+// stack on every Add has a real cost; enable it only while diagnosing a
+// suspected leak.
+func (w *ContextsMap) EnableLeakTracking(enabled bool) {
+	v := int32(0)
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&w.leakTracking, v)
+}
+
+// DumpLeaks writes a diagnostic report to out listing every WorkflowContext
+// that has been alive for at least minAge, along with the stack trace
+// captured at Add time if EnableLeakTracking was on when it was added.
+// Intended to be wired up to an admin endpoint or a periodic diagnostic job
+// so operators can see where a suspected leak originated.
+func (w *ContextsMap) DumpLeaks(out io.Writer, minAge time.Duration) {
+	now := time.Now()
+	for _, shard := range w.shards {
+		shard.RLock()
+		for id, entry := range shard.contexts {
+			age := now.Sub(time.Unix(0, entry.created))
+			if age < minAge {
+				continue
+			}
+
+			name := "<unknown>"
+			if n := entry.ctx.GetWorkflowName(); n != nil {
+				name = *n
+			}
+
+			fmt.Fprintf(out, "contextID=%d workflow=%s age=%s\n", id, name, age)
+			if len(entry.stack) > 0 {
+				out.Write(entry.stack)
+				fmt.Fprintln(out)
+			}
+		}
+		shard.RUnlock()
+	}
 }
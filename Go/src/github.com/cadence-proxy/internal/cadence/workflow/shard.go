@@ -0,0 +1,93 @@
+//-----------------------------------------------------------------------------
+// FILE:		shard.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflow
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// contextShard is one stripe of a ContextsMap.  Each shard owns its own
+// lock and its own inner map, so Add/Remove/Get calls against different
+// shards never contend with each other.
+type contextShard struct {
+	sync.RWMutex
+	contexts map[int64]*contextEntry
+}
+
+// contextEntry wraps a *Context with the bookkeeping the TTL sweeper and the
+// leak tracker need: the time the entry was last touched (by Add or Touch),
+// the time it was created, and - only when leak tracking is enabled - the
+// stack trace captured when it was added.  lastTouch is accessed atomically
+// since Get/Touch only take the shard's read lock; created and stack are
+// written once at construction and never mutated afterward, so they need no
+// synchronization of their own.
+type contextEntry struct {
+	ctx       *Context
+	created   int64 // unix nanos
+	lastTouch int64 // unix nanos
+	stack     []byte
+}
+
+// newContextEntry wraps ctx in a contextEntry, stamped with the current time.
+func newContextEntry(ctx *Context) *contextEntry {
+	now := time.Now().UnixNano()
+	return &contextEntry{ctx: ctx, created: now, lastTouch: now}
+}
+
+// touch resets the entry's last-touch time to now.
+func (e *contextEntry) touch() {
+	atomic.StoreInt64(&e.lastTouch, time.Now().UnixNano())
+}
+
+// idleSince returns how long it has been since the entry was last touched.
+func (e *contextEntry) idleSince(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, atomic.LoadInt64(&e.lastTouch)))
+}
+
+// evictedContext is a (contextID, *Context) pair collected by the eviction
+// sweeper while a shard is locked, so onEvict can be invoked after the
+// shard's lock is released.
+type evictedContext struct {
+	contextID int64
+	ctx       *Context
+}
+
+// defaultShardCount returns the default number of shards a ContextsMap is
+// created with: runtime.GOMAXPROCS(0)*4, rounded up to the next power of
+// two so shard selection can be done with a cheap bitmask instead of a
+// modulo.
+func defaultShardCount() int {
+	return nextPowerOfTwo(runtime.GOMAXPROCS(0) * 4)
+}
+
+// nextPowerOfTwo rounds n up to the next power of two, with a floor of 1.
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+
+	return p
+}
@@ -0,0 +1,156 @@
+//-----------------------------------------------------------------------------
+// FILE:		headers.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package headers implements transparent gRPC/Thrift metadata forwarding
+// for cadence-proxy, borrowing the pattern Temporal's workflow-service proxy
+// uses: incoming request metadata is filtered through an allow/deny list and
+// re-attached to the outbound backend call, and response metadata is
+// filtered the same way before being handed back to the .NET client.
+package headers
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+type (
+
+	// Config controls which incoming/outgoing metadata keys cadence-proxy
+	// forwards between the .NET client and the Cadence/Temporal backend.
+	Config struct {
+
+		// DisableHeaderForwarding turns off header forwarding entirely when
+		// true, regardless of Allow/Deny.
+		DisableHeaderForwarding bool
+
+		// Allow, if non-empty, restricts forwarding to only these header
+		// keys (case-insensitive).  An empty Allow forwards everything not
+		// excluded by Deny.
+		Allow []string
+
+		// Deny lists header keys (case-insensitive) that are never
+		// forwarded, regardless of Allow.  Evaluated after Allow, so Deny
+		// always wins for a key present in both.
+		Deny []string
+	}
+)
+
+// defaultConfig is the process-wide header forwarding policy, set once at
+// startup via SetDefaultConfig.
+var defaultConfig Config
+
+// SetDefaultConfig sets the process-wide header forwarding policy.  This is
+// expected to be called once during startup.
+func SetDefaultConfig(cfg Config) {
+	defaultConfig = cfg
+}
+
+// DefaultConfig returns the process-wide header forwarding policy.
+func DefaultConfig() Config {
+	return defaultConfig
+}
+
+// permits reports whether key should be forwarded under cfg.
+func (cfg Config) permits(key string) bool {
+	if cfg.DisableHeaderForwarding {
+		return false
+	}
+
+	for _, denied := range cfg.Deny {
+		if strings.EqualFold(denied, key) {
+			return false
+		}
+	}
+
+	if len(cfg.Allow) == 0 {
+		return true
+	}
+
+	for _, allowed := range cfg.Allow {
+		if strings.EqualFold(allowed, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Filter returns the subset of headers whose keys are permitted by cfg.  A
+// nil or empty headers map returns nil.
+func Filter(headers map[string][]string, cfg Config) map[string][]string {
+	if cfg.DisableHeaderForwarding || len(headers) == 0 {
+		return nil
+	}
+
+	filtered := make(map[string][]string, len(headers))
+	for key, values := range headers {
+		if cfg.permits(key) {
+			filtered[key] = values
+		}
+	}
+
+	if len(filtered) == 0 {
+		return nil
+	}
+
+	return filtered
+}
+
+// FromIncomingContext extracts the forwardable subset of an inbound gRPC
+// context's incoming metadata, per cfg's allow/deny lists.
+func FromIncomingContext(ctx context.Context, cfg Config) map[string][]string {
+	if cfg.DisableHeaderForwarding {
+		return nil
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	return Filter(map[string][]string(md), cfg)
+}
+
+// FromThrift extracts the forwardable subset of a Thrift request's string
+// header map, per cfg's allow/deny lists.  Cadence's Thrift transport
+// represents headers as map[string]string rather than gRPC's
+// map[string][]string, so each value is wrapped in a single-element slice.
+func FromThrift(thriftHeaders map[string]string, cfg Config) map[string][]string {
+	if cfg.DisableHeaderForwarding || len(thriftHeaders) == 0 {
+		return nil
+	}
+
+	headers := make(map[string][]string, len(thriftHeaders))
+	for key, value := range thriftHeaders {
+		headers[key] = []string{value}
+	}
+
+	return Filter(headers, cfg)
+}
+
+// ToOutgoingContext attaches headers to ctx's outgoing gRPC metadata so they
+// propagate to the backend's Describe* call.  If headers is empty, ctx is
+// returned unchanged.
+func ToOutgoingContext(ctx context.Context, headers map[string][]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+
+	return metadata.NewOutgoingContext(ctx, metadata.MD(headers))
+}
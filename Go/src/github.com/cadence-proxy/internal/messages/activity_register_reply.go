@@ -0,0 +1,109 @@
+//-----------------------------------------------------------------------------
+// FILE:		activity_register_reply.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"time"
+
+	internal "github.com/cadence-proxy/internal"
+)
+
+type (
+
+	// ActivityRegisterReply is a ActivityReply of MessageType
+	// ActivityRegisterReply.  It holds a reference to a ActivityReply in
+	// memory and answers a ActivityRegisterRequest.
+	ActivityRegisterReply struct {
+		*ActivityReply
+	}
+
+	// ActivityRegisterOptions reports the registration options actually
+	// applied to an activity once the worker merges an ActivityRegisterRequest's
+	// explicit fields with its own built-in defaults, so a caller that left
+	// some of the request's fields unset (timeouts, retry policy, task
+	// list, concurrency) can still see what the worker ended up using.
+	ActivityRegisterOptions struct {
+		TaskList               string
+		Concurrency            int32
+		ScheduleToStartTimeout time.Duration
+		StartToCloseTimeout    time.Duration
+		ScheduleToCloseTimeout time.Duration
+		HeartbeatTimeout       time.Duration
+		RetryPolicy            *ActivityRetryPolicy
+	}
+)
+
+// NewActivityRegisterReply is the default constructor for
+// a ActivityRegisterReply
+//
+// returns *ActivityRegisterReply -> a pointer to a newly initialized
+// ActivityRegisterReply in memory
+func NewActivityRegisterReply() *ActivityRegisterReply {
+	reply := new(ActivityRegisterReply)
+	reply.ActivityReply = NewActivityReply()
+	reply.SetType(internal.ActivityRegisterReply)
+
+	return reply
+}
+
+// GetEffectiveOptions gets a ActivityRegisterReply's EffectiveOptions
+// field from its properties map: the registration options the worker
+// actually applied after merging the request's explicit fields with its
+// own defaults.
+//
+// returns *ActivityRegisterOptions -> the effective options applied to the
+// registered activity, or nil if the reply carries an error instead
+func (reply *ActivityRegisterReply) GetEffectiveOptions() *ActivityRegisterOptions {
+	var options *ActivityRegisterOptions
+	if err := reply.GetJSONProperty("EffectiveOptions", &options); err != nil {
+		return nil
+	}
+
+	return options
+}
+
+// SetEffectiveOptions sets a ActivityRegisterReply's EffectiveOptions
+// field in its properties map: the registration options the worker
+// actually applied after merging the request's explicit fields with its
+// own defaults.
+//
+// param value *ActivityRegisterOptions -> the effective options applied to
+// the registered activity, or nil to clear it
+func (reply *ActivityRegisterReply) SetEffectiveOptions(value *ActivityRegisterOptions) {
+	reply.SetJSONProperty("EffectiveOptions", value)
+}
+
+// -------------------------------------------------------------------------
+// IProxyMessage interface methods for implementing the IProxyMessage interface
+
+// Clone inherits docs from ActivityReply.Clone()
+func (reply *ActivityRegisterReply) Clone() IProxyMessage {
+	activityRegisterReply := NewActivityRegisterReply()
+	var messageClone IProxyMessage = activityRegisterReply
+	reply.CopyTo(messageClone)
+
+	return messageClone
+}
+
+// CopyTo inherits docs from ActivityReply.CopyTo()
+func (reply *ActivityRegisterReply) CopyTo(target IProxyMessage) {
+	reply.ActivityReply.CopyTo(target)
+	if v, ok := target.(*ActivityRegisterReply); ok {
+		v.SetEffectiveOptions(reply.GetEffectiveOptions())
+	}
+}
@@ -18,6 +18,9 @@
 package messages
 
 import (
+	"fmt"
+	"time"
+
 	internal "github.com/cadence-proxy/internal"
 )
 
@@ -34,6 +37,18 @@ type (
 	ActivityRegisterRequest struct {
 		*ActivityRequest
 	}
+
+	// ActivityRetryPolicy describes how a registered activity's invocations
+	// should be retried on failure.  It mirrors the retry policy shape the
+	// backend SDKs already expose, so it can be passed through unchanged
+	// regardless of which backend.Current() is active.
+	ActivityRetryPolicy struct {
+		InitialInterval          time.Duration
+		BackoffCoefficient       float64
+		MaximumInterval          time.Duration
+		MaximumAttempts          int32
+		NonRetriableErrorReasons []string
+	}
 )
 
 // NewActivityRegisterRequest is the default constructor for a ActivityRegisterRequest
@@ -87,6 +102,286 @@ func (request *ActivityRegisterRequest) SetDomain(value *string) {
 	request.SetStringProperty("Domain", value)
 }
 
+// GetTaskList gets a ActivityRegisterRequest's TaskList field from its
+// properties map.  Overrides the task list the activity is registered
+// against, instead of the worker's default.
+//
+// returns *string -> *string representing the task list override, or nil
+// to use the worker's default task list
+func (request *ActivityRegisterRequest) GetTaskList() *string {
+	return request.GetStringProperty("TaskList")
+}
+
+// SetTaskList sets a ActivityRegisterRequest's TaskList field in its
+// properties map.  Overrides the task list the activity is registered
+// against, instead of the worker's default.
+//
+// param value *string -> *string representing the task list override, or
+// nil to use the worker's default task list
+func (request *ActivityRegisterRequest) SetTaskList(value *string) {
+	request.SetStringProperty("TaskList", value)
+}
+
+// GetConcurrency gets a ActivityRegisterRequest's Concurrency field from
+// its properties map.  Caps the number of invocations of this activity the
+// worker will execute at once, independent of the worker's overall
+// activity concurrency limit.
+//
+// returns *int32 -> *int32 representing the concurrency cap, or nil for no
+// activity-specific cap
+func (request *ActivityRegisterRequest) GetConcurrency() *int32 {
+	return request.GetInt32Property("Concurrency")
+}
+
+// SetConcurrency sets a ActivityRegisterRequest's Concurrency field in its
+// properties map.  Caps the number of invocations of this activity the
+// worker will execute at once, independent of the worker's overall
+// activity concurrency limit.
+//
+// param value *int32 -> *int32 representing the concurrency cap, or nil
+// for no activity-specific cap
+func (request *ActivityRegisterRequest) SetConcurrency(value *int32) {
+	request.SetInt32Property("Concurrency", value)
+}
+
+// GetScheduleToStartTimeout gets a ActivityRegisterRequest's
+// ScheduleToStartTimeout field from its properties map.  The default
+// maximum time this activity may wait in a task list before a worker picks
+// it up.
+//
+// returns time.Duration -> the default schedule-to-start timeout
+func (request *ActivityRegisterRequest) GetScheduleToStartTimeout() time.Duration {
+	return request.GetTimeSpanProperty("ScheduleToStartTimeout")
+}
+
+// SetScheduleToStartTimeout sets a ActivityRegisterRequest's
+// ScheduleToStartTimeout field in its properties map.  The default maximum
+// time this activity may wait in a task list before a worker picks it up.
+//
+// param value time.Duration -> the default schedule-to-start timeout
+func (request *ActivityRegisterRequest) SetScheduleToStartTimeout(value time.Duration) {
+	request.SetTimeSpanProperty("ScheduleToStartTimeout", value)
+}
+
+// GetStartToCloseTimeout gets a ActivityRegisterRequest's
+// StartToCloseTimeout field from its properties map.  The default maximum
+// time this activity may run once a worker starts it.
+//
+// returns time.Duration -> the default start-to-close timeout
+func (request *ActivityRegisterRequest) GetStartToCloseTimeout() time.Duration {
+	return request.GetTimeSpanProperty("StartToCloseTimeout")
+}
+
+// SetStartToCloseTimeout sets a ActivityRegisterRequest's
+// StartToCloseTimeout field in its properties map.  The default maximum
+// time this activity may run once a worker starts it.
+//
+// param value time.Duration -> the default start-to-close timeout
+func (request *ActivityRegisterRequest) SetStartToCloseTimeout(value time.Duration) {
+	request.SetTimeSpanProperty("StartToCloseTimeout", value)
+}
+
+// GetScheduleToCloseTimeout gets a ActivityRegisterRequest's
+// ScheduleToCloseTimeout field from its properties map.  The default
+// maximum end-to-end time this activity may take, from being scheduled to
+// completing, including retries.
+//
+// returns time.Duration -> the default schedule-to-close timeout
+func (request *ActivityRegisterRequest) GetScheduleToCloseTimeout() time.Duration {
+	return request.GetTimeSpanProperty("ScheduleToCloseTimeout")
+}
+
+// SetScheduleToCloseTimeout sets a ActivityRegisterRequest's
+// ScheduleToCloseTimeout field in its properties map.  The default maximum
+// end-to-end time this activity may take, from being scheduled to
+// completing, including retries.
+//
+// param value time.Duration -> the default schedule-to-close timeout
+func (request *ActivityRegisterRequest) SetScheduleToCloseTimeout(value time.Duration) {
+	request.SetTimeSpanProperty("ScheduleToCloseTimeout", value)
+}
+
+// GetHeartbeatTimeout gets a ActivityRegisterRequest's HeartbeatTimeout
+// field from its properties map.  The default maximum time this activity
+// may run between RecordActivityHeartbeat calls before it's considered
+// dead.
+//
+// returns time.Duration -> the default heartbeat timeout
+func (request *ActivityRegisterRequest) GetHeartbeatTimeout() time.Duration {
+	return request.GetTimeSpanProperty("HeartbeatTimeout")
+}
+
+// SetHeartbeatTimeout sets a ActivityRegisterRequest's HeartbeatTimeout
+// field in its properties map.  The default maximum time this activity may
+// run between RecordActivityHeartbeat calls before it's considered dead.
+//
+// param value time.Duration -> the default heartbeat timeout
+func (request *ActivityRegisterRequest) SetHeartbeatTimeout(value time.Duration) {
+	request.SetTimeSpanProperty("HeartbeatTimeout", value)
+}
+
+// GetRetryPolicy gets a ActivityRegisterRequest's RetryPolicy field from
+// its properties map.  The default retry policy applied to invocations of
+// this activity that don't specify their own.
+//
+// returns *ActivityRetryPolicy -> the default retry policy, or nil if
+// invocations of this activity aren't retried by default
+func (request *ActivityRegisterRequest) GetRetryPolicy() *ActivityRetryPolicy {
+	var policy *ActivityRetryPolicy
+	if err := request.GetJSONProperty("RetryPolicy", &policy); err != nil {
+		return nil
+	}
+
+	return policy
+}
+
+// SetRetryPolicy sets a ActivityRegisterRequest's RetryPolicy field in its
+// properties map.  The default retry policy applied to invocations of this
+// activity that don't specify their own.
+//
+// param value *ActivityRetryPolicy -> the default retry policy, or nil if
+// invocations of this activity aren't retried by default
+func (request *ActivityRegisterRequest) SetRetryPolicy(value *ActivityRetryPolicy) {
+	request.SetJSONProperty("RetryPolicy", value)
+}
+
+// GetTags gets a ActivityRegisterRequest's Tags field from its properties
+// map.  Arbitrary metadata attached to the registration, surfaced to
+// tooling such as the web UI and metrics, but otherwise unused by the
+// proxy itself.
+//
+// returns map[string]string -> the activity's tags, or nil if none are set
+func (request *ActivityRegisterRequest) GetTags() map[string]string {
+	var tags map[string]string
+	if err := request.GetJSONProperty("Tags", &tags); err != nil {
+		return nil
+	}
+
+	return tags
+}
+
+// SetTags sets a ActivityRegisterRequest's Tags field in its properties
+// map.  Arbitrary metadata attached to the registration, surfaced to
+// tooling such as the web UI and metrics, but otherwise unused by the proxy
+// itself.
+//
+// param value map[string]string -> the activity's tags, or nil to clear
+// them
+func (request *ActivityRegisterRequest) SetTags(value map[string]string) {
+	request.SetJSONProperty("Tags", value)
+}
+
+// GetArgSchema gets a ActivityRegisterRequest's ArgSchema field from its
+// properties map.  Describes the activity's positional arguments, letting
+// a cross-language caller discover its signature at runtime instead of
+// hand-syncing it with the Go implementation.
+//
+// returns []ParamSchema -> the activity's argument schema, or nil if none
+// is declared
+func (request *ActivityRegisterRequest) GetArgSchema() []ParamSchema {
+	var schema []ParamSchema
+	if err := request.GetJSONProperty("ArgSchema", &schema); err != nil {
+		return nil
+	}
+
+	return schema
+}
+
+// SetArgSchema sets a ActivityRegisterRequest's ArgSchema field in its
+// properties map.  Describes the activity's positional arguments, letting
+// a cross-language caller discover its signature at runtime instead of
+// hand-syncing it with the Go implementation.
+//
+// param value []ParamSchema -> the activity's argument schema, or nil to
+// leave it undeclared
+func (request *ActivityRegisterRequest) SetArgSchema(value []ParamSchema) {
+	request.SetJSONProperty("ArgSchema", value)
+}
+
+// GetResultSchema gets a ActivityRegisterRequest's ResultSchema field from
+// its properties map.  Describes the activity's return value the same way
+// ArgSchema describes its arguments.
+//
+// returns []ParamSchema -> the activity's result schema, or nil if none is
+// declared
+func (request *ActivityRegisterRequest) GetResultSchema() []ParamSchema {
+	var schema []ParamSchema
+	if err := request.GetJSONProperty("ResultSchema", &schema); err != nil {
+		return nil
+	}
+
+	return schema
+}
+
+// SetResultSchema sets a ActivityRegisterRequest's ResultSchema field in
+// its properties map.  Describes the activity's return value the same way
+// ArgSchema describes its arguments.
+//
+// param value []ParamSchema -> the activity's result schema, or nil to
+// leave it undeclared
+func (request *ActivityRegisterRequest) SetResultSchema(value []ParamSchema) {
+	request.SetJSONProperty("ResultSchema", value)
+}
+
+// GetAllowSchemaEvolution gets a ActivityRegisterRequest's
+// AllowSchemaEvolution field from its properties map.  When true, a
+// re-registration of this activity is accepted even if its ArgSchema or
+// ResultSchema is incompatible with the version already registered; when
+// false (the default), an incompatible re-registration is rejected.
+//
+// returns bool -> true if an incompatible schema change should be allowed
+func (request *ActivityRegisterRequest) GetAllowSchemaEvolution() bool {
+	return request.GetBoolProperty("AllowSchemaEvolution")
+}
+
+// SetAllowSchemaEvolution sets a ActivityRegisterRequest's
+// AllowSchemaEvolution field in its properties map.  When true, a
+// re-registration of this activity is accepted even if its ArgSchema or
+// ResultSchema is incompatible with the version already registered; when
+// false (the default), an incompatible re-registration is rejected.
+//
+// param value bool -> true if an incompatible schema change should be
+// allowed
+func (request *ActivityRegisterRequest) SetAllowSchemaEvolution(value bool) {
+	request.SetBoolProperty("AllowSchemaEvolution", value)
+}
+
+// CheckSchemaEvolution reports the error the registration handler should
+// reject this request with, given the ArgSchema and ResultSchema already
+// registered for an activity of this Name (prior is nil, nil if this is
+// the first registration).  It returns nil if the request should be
+// accepted: either because there is no prior registration, request's
+// ArgSchema and ResultSchema are both IsSchemaCompatible with it, or the
+// request sets AllowSchemaEvolution.
+//
+// returns error -> a *CodedError with code ErrorCodeArgumentMismatch
+// naming the incompatible property, or nil if re-registration is allowed
+func (request *ActivityRegisterRequest) CheckSchemaEvolution(priorArgSchema, priorResultSchema []ParamSchema) error {
+	if request.GetAllowSchemaEvolution() {
+		return nil
+	}
+
+	name := stringOrEmpty(request.GetName())
+
+	if !IsSchemaCompatible(priorArgSchema, request.GetArgSchema()) {
+		return &CodedError{
+			Type:    "ArgumentMismatch",
+			Message: fmt.Sprintf("activity %q: ArgSchema is incompatible with the already-registered schema", name),
+			Code:    ErrorCodeArgumentMismatch,
+		}
+	}
+
+	if !IsSchemaCompatible(priorResultSchema, request.GetResultSchema()) {
+		return &CodedError{
+			Type:    "ArgumentMismatch",
+			Message: fmt.Sprintf("activity %q: ResultSchema is incompatible with the already-registered schema", name),
+			Code:    ErrorCodeArgumentMismatch,
+		}
+	}
+
+	return nil
+}
+
 // -------------------------------------------------------------------------
 // IProxyMessage interface methods for implementing the IProxyMessage interface
 
@@ -105,5 +400,16 @@ func (request *ActivityRegisterRequest) CopyTo(target IProxyMessage) {
 	if v, ok := target.(*ActivityRegisterRequest); ok {
 		v.SetName(request.GetName())
 		v.SetDomain(request.GetDomain())
+		v.SetTaskList(request.GetTaskList())
+		v.SetConcurrency(request.GetConcurrency())
+		v.SetScheduleToStartTimeout(request.GetScheduleToStartTimeout())
+		v.SetStartToCloseTimeout(request.GetStartToCloseTimeout())
+		v.SetScheduleToCloseTimeout(request.GetScheduleToCloseTimeout())
+		v.SetHeartbeatTimeout(request.GetHeartbeatTimeout())
+		v.SetRetryPolicy(request.GetRetryPolicy())
+		v.SetTags(request.GetTags())
+		v.SetArgSchema(request.GetArgSchema())
+		v.SetResultSchema(request.GetResultSchema())
+		v.SetAllowSchemaEvolution(request.GetAllowSchemaEvolution())
 	}
 }
@@ -19,7 +19,12 @@ package messages
 
 import (
 	internal "github.com/cadence-proxy/internal"
+	"github.com/cadence-proxy/internal/backend"
+	cadencebackend "github.com/cadence-proxy/internal/backend/cadence"
+	temporalbackend "github.com/cadence-proxy/internal/backend/temporal"
+
 	cadenceshared "go.uber.org/cadence/.gen/go/shared"
+	"go.temporal.io/api/workflowservice/v1"
 )
 
 type (
@@ -46,27 +51,69 @@ func NewDescribeTaskListReply() *DescribeTaskListReply {
 }
 
 // GetResult gets the DescribeTaskListReply's Result property from its
-// properties map, describes the task list details.
+// properties map, describes the task list details.  The concrete response
+// type depends on the active backend.Current(), but it is always decoded
+// from the same "Result" JSON property slot so the wire format is stable
+// across backends.
 //
-// returns *cadenceshared.DescribeTaskListResponse -> the response to the cadence
+// returns backend.DescribeTaskListResponse -> the response to the
 // describe task list request.
-func (reply *DescribeTaskListReply) GetResult() *cadenceshared.DescribeTaskListResponse {
-	resp := new(cadenceshared.DescribeTaskListResponse)
-	err := reply.GetJSONProperty("Result", resp)
-	if err != nil {
-		return nil
-	}
+func (reply *DescribeTaskListReply) GetResult() backend.DescribeTaskListResponse {
+	switch backend.Current() {
+	case backend.Temporal:
+		resp := new(workflowservice.DescribeTaskQueueResponse)
+		if err := reply.GetJSONProperty("Result", resp); err != nil {
+			return nil
+		}
+
+		return temporalbackend.NewDescribeTaskListResponse(resp)
+	default:
+		resp := new(cadenceshared.DescribeTaskListResponse)
+		if err := reply.GetJSONProperty("Result", resp); err != nil {
+			return nil
+		}
 
-	return resp
+		return cadencebackend.NewDescribeTaskListResponse(resp)
+	}
 }
 
 // SetResult sets the DescribeTaskListReply's Result property in its
 // properties map, describes the task list details.
 //
-// param value cadenceshared*.DescribeTaskListResponse -> the response to the cadence
+// param value backend.DescribeTaskListResponse -> the response to the
 // describe task list request.
-func (reply *DescribeTaskListReply) SetResult(value *cadenceshared.DescribeTaskListResponse) {
-	reply.SetJSONProperty("Result", value)
+func (reply *DescribeTaskListReply) SetResult(value backend.DescribeTaskListResponse) {
+	if value == nil {
+		reply.SetJSONProperty("Result", nil)
+		return
+	}
+
+	reply.SetJSONProperty("Result", value.Unwrap())
+}
+
+// GetHeaders gets the DescribeTaskListReply's Headers property from its
+// properties map: the gRPC/Thrift response metadata (server version, shard,
+// tracing/baggage, ...) forwarded back from the describe task list call,
+// filtered through the configured headers.Config.
+//
+// returns map[string][]string -> the forwarded response headers, or nil if
+// none were set.
+func (reply *DescribeTaskListReply) GetHeaders() map[string][]string {
+	var result map[string][]string
+	if err := reply.GetJSONProperty("Headers", &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// SetHeaders sets the DescribeTaskListReply's Headers property in its
+// properties map: the gRPC/Thrift response metadata forwarded back from the
+// describe task list call.
+//
+// param value map[string][]string -> the response headers to forward.
+func (reply *DescribeTaskListReply) SetHeaders(value map[string][]string) {
+	reply.SetJSONProperty("Headers", value)
 }
 
 // -------------------------------------------------------------------------
@@ -86,5 +133,6 @@ func (reply *DescribeTaskListReply) CopyTo(target IProxyMessage) {
 	reply.ProxyReply.CopyTo(target)
 	if v, ok := target.(*DescribeTaskListReply); ok {
 		v.SetResult(reply.GetResult())
+		v.SetHeaders(reply.GetHeaders())
 	}
 }
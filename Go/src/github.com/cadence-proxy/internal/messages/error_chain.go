@@ -0,0 +1,200 @@
+//-----------------------------------------------------------------------------
+// FILE:		error_chain.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"errors"
+	"fmt"
+)
+
+type (
+
+	// ErrorCode is a stable classification for an error carried by a
+	// ProxyReply that survives the JSON round-trip, independent of the
+	// concrete Go type (or backend SDK) that produced the error.
+	ErrorCode string
+
+	// CodedError is one frame of a ProxyReply's error chain, reconstructed
+	// from the wire.  It implements Unwrap() error so the chain built by
+	// ProxyReply.GetError() supports errors.Is/errors.As the same way a
+	// chain built locally with fmt.Errorf("...: %w", ...) would.
+	CodedError struct {
+		Type    string
+		Message string
+		Code    ErrorCode
+		cause   error
+	}
+
+	// errorFrame is the JSON shape a single CodedError is marshaled to in a
+	// ProxyReply's Errors property.
+	errorFrame struct {
+		Type    string    `json:"Type"`
+		Message string    `json:"Message"`
+		Code    ErrorCode `json:"Code"`
+	}
+)
+
+const (
+
+	// ErrorCodeCancelled marks a frame produced by a cancelled workflow or
+	// activity.
+	ErrorCodeCancelled ErrorCode = "Cancelled"
+
+	// ErrorCodeTimedOut marks a frame produced by a workflow or activity
+	// that exceeded one of its timeouts.
+	ErrorCodeTimedOut ErrorCode = "TimedOut"
+
+	// ErrorCodeTerminated marks a frame produced by a terminated workflow.
+	ErrorCodeTerminated ErrorCode = "Terminated"
+
+	// ErrorCodePanic marks a frame recovered from a panic.
+	ErrorCodePanic ErrorCode = "Panic"
+
+	// ErrorCodeGeneric marks a frame that doesn't match any other
+	// ErrorCode.  This is the default for an error with no registered
+	// sentinel.
+	ErrorCodeGeneric ErrorCode = "Generic"
+
+	// ErrorCodeCustom marks a frame an application explicitly classified
+	// itself, rather than one this package inferred.
+	ErrorCodeCustom ErrorCode = "Custom"
+
+	// ErrorCodeArgumentMismatch marks a frame produced when an invocation's
+	// argument payload doesn't satisfy the target activity or workflow's
+	// registered ParamSchema.
+	ErrorCodeArgumentMismatch ErrorCode = "ArgumentMismatch"
+)
+
+// sentinelsByCode maps the well-known ErrorCodes to the sentinel error
+// values a caller compares against with errors.Is.  A code can have more
+// than one registered sentinel - e.g. Cadence's and Temporal's own
+// canceled-error values both map to ErrorCodeCancelled - since only one
+// backend.Current() is active at a time, but this package doesn't know
+// which.  It starts out empty so this package has no compile-time
+// dependency on any particular backend SDK; a backend package registers
+// its own sentinels (e.g. cadence's ErrCanceled, temporal's ErrCanceled)
+// during init via RegisterSentinel.
+var sentinelsByCode = make(map[ErrorCode][]error)
+
+// RegisterSentinel associates code with sentinel, so that classify can
+// recognize sentinel when flattening an error chain, and so that a
+// reconstructed CodedError's Is method can match errors.Is(err, sentinel).
+// It may be called more than once for the same code to register sentinels
+// from multiple backends.
+func RegisterSentinel(code ErrorCode, sentinel error) {
+	sentinelsByCode[code] = append(sentinelsByCode[code], sentinel)
+}
+
+// Error implements the error interface.
+func (e *CodedError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the next frame in the chain, or nil at the tail, giving a
+// reconstructed CodedError chain the same errors.Unwrap semantics as a
+// chain built locally with fmt.Errorf("...: %w", ...).
+func (e *CodedError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is one of the sentinels registered for e.Code,
+// so a caller can do errors.Is(reply.GetError(), cadence.ErrCanceled)
+// without needing the concrete backend error type that produced this
+// frame.
+func (e *CodedError) Is(target error) bool {
+	for _, sentinel := range sentinelsByCode[e.Code] {
+		if target == sentinel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// classify inspects err and produces the errorFrame it should be serialized
+// as: a CodedError is carried through with its existing Type/Message/Code,
+// and any other error is classified against the registered sentinels,
+// falling back to ErrorCodeGeneric.
+func classify(err error) errorFrame {
+	if coded, ok := err.(*CodedError); ok {
+		return errorFrame{Type: coded.Type, Message: coded.Message, Code: coded.Code}
+	}
+
+	code := ErrorCodeGeneric
+outer:
+	for candidate, sentinels := range sentinelsByCode {
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) {
+				code = candidate
+				break outer
+			}
+		}
+	}
+
+	return errorFrame{Type: fmt.Sprintf("%T", err), Message: err.Error(), Code: code}
+}
+
+// flattenChain walks err depth-first, following both the single-cause
+// Unwrap() error convention and the errors.Join multi-cause
+// Unwrap() []error convention, and returns the frames it should be
+// serialized as, outermost first.
+func flattenChain(err error) []errorFrame {
+	var frames []errorFrame
+
+	var walk func(error)
+	walk = func(e error) {
+		if e == nil {
+			return
+		}
+
+		frames = append(frames, classify(e))
+
+		switch x := e.(type) {
+		case interface{ Unwrap() []error }:
+			for _, sub := range x.Unwrap() {
+				walk(sub)
+			}
+		case interface{ Unwrap() error }:
+			walk(x.Unwrap())
+		}
+	}
+
+	walk(err)
+
+	return frames
+}
+
+// chainFrames reconstructs frames as a linked chain of *CodedError, each
+// wrapping the next, and returns the head.  It returns nil for an empty
+// slice.
+func chainFrames(frames []errorFrame) error {
+	if len(frames) == 0 {
+		return nil
+	}
+
+	coded := make([]*CodedError, len(frames))
+	for i, f := range frames {
+		coded[i] = &CodedError{Type: f.Type, Message: f.Message, Code: f.Code}
+	}
+
+	for i := 0; i < len(coded)-1; i++ {
+		coded[i].cause = coded[i+1]
+	}
+
+	return coded[0]
+}
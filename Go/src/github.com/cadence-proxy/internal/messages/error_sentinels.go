@@ -0,0 +1,45 @@
+//-----------------------------------------------------------------------------
+// FILE:		error_sentinels.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	cadencebackend "github.com/cadence-proxy/internal/backend/cadence"
+	temporalbackend "github.com/cadence-proxy/internal/backend/temporal"
+)
+
+// init registers both backends' sentinel errors against the well-known
+// ErrorCodes, so classify can recognize a real Cadence or Temporal error by
+// code instead of always falling back to ErrorCodeGeneric, and so a
+// reconstructed CodedError's Is method can match
+// errors.Is(reply.GetError(), cadencebackend.ErrCanceled) regardless of
+// which backend.Current() produced the error.  This lives here, rather
+// than in the backend packages themselves, because backend/cadence and
+// backend/temporal are already imported by this package (for the
+// Describe* response wrappers) and importing messages back from them
+// would be a cycle.
+func init() {
+	RegisterSentinel(ErrorCodeCancelled, cadencebackend.ErrCanceled)
+	RegisterSentinel(ErrorCodeTimedOut, cadencebackend.ErrTimedOut)
+	RegisterSentinel(ErrorCodeTerminated, cadencebackend.ErrTerminated)
+	RegisterSentinel(ErrorCodePanic, cadencebackend.ErrPanic)
+
+	RegisterSentinel(ErrorCodeCancelled, temporalbackend.ErrCanceled)
+	RegisterSentinel(ErrorCodeTimedOut, temporalbackend.ErrTimedOut)
+	RegisterSentinel(ErrorCodeTerminated, temporalbackend.ErrTerminated)
+	RegisterSentinel(ErrorCodePanic, temporalbackend.ErrPanic)
+}
@@ -0,0 +1,106 @@
+//-----------------------------------------------------------------------------
+// FILE:		param_schema.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import "fmt"
+
+// ParamSchema describes a single positional activity or workflow
+// parameter, letting a cross-language caller (C#, Java, ...) discover a
+// registered activity or workflow's signature at runtime instead of
+// hand-syncing it with the Go implementation.
+type ParamSchema struct {
+
+	// Name is the parameter's name, for documentation and error messages;
+	// positional matching against an invocation's argument list is done by
+	// index, not by Name.
+	Name string
+
+	// GoType is the Go type the parameter is unmarshaled into, e.g.
+	// "string" or "*MyWorkflowArgs".
+	GoType string
+
+	// JSONSchema is a JSON Schema document describing the shape of the
+	// argument, used to validate an invocation's payload before it's
+	// unmarshaled into GoType.
+	JSONSchema string
+
+	// Required is false if Default should be substituted when the
+	// invocation's argument list doesn't supply this parameter.
+	Required bool
+
+	// Default is the value substituted for this parameter when it's
+	// omitted and Required is false.
+	Default interface{}
+}
+
+// IsSchemaCompatible reports whether next can replace prior as the
+// registered schema for an activity or workflow without breaking callers
+// that were written against prior: every parameter prior marked Required
+// must still be present, at the same position, with the same GoType, in
+// next.  next may append new parameters, and may relax a prior's Required
+// parameter to optional, but it may not remove, reorder, retype, or
+// tighten an existing one.
+func IsSchemaCompatible(prior, next []ParamSchema) bool {
+	if len(next) < len(prior) {
+		return false
+	}
+
+	for i, p := range prior {
+		if next[i].GoType != p.GoType {
+			return false
+		}
+
+		if !p.Required && next[i].Required {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ValidateArgs checks args - an invocation's raw, positional argument
+// payloads, in the order they'll be unmarshaled - against schema before
+// they're dispatched to user code.  It reports the first mismatch: more
+// args than schema declares, or a missing arg at a position schema marks
+// Required.  It does not unmarshal args or validate them against a
+// parameter's JSONSchema; that happens once the args are decoded into
+// their GoType.
+//
+// returns error -> a *CodedError with code ErrorCodeArgumentMismatch
+// describing the first mismatch, or nil if args satisfies schema
+func ValidateArgs(schema []ParamSchema, args [][]byte) error {
+	if len(args) > len(schema) {
+		return &CodedError{
+			Type:    "ArgumentMismatch",
+			Message: fmt.Sprintf("expected at most %d argument(s), got %d", len(schema), len(args)),
+			Code:    ErrorCodeArgumentMismatch,
+		}
+	}
+
+	for i, p := range schema {
+		if p.Required && i >= len(args) {
+			return &CodedError{
+				Type:    "ArgumentMismatch",
+				Message: fmt.Sprintf("missing required argument %q at position %d", p.Name, i),
+				Code:    ErrorCodeArgumentMismatch,
+			}
+		}
+	}
+
+	return nil
+}
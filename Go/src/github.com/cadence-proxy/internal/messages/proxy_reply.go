@@ -18,6 +18,8 @@
 package messages
 
 import (
+	"errors"
+
 	internal "github.com/cadence-proxy/internal"
 	"github.com/cadence-proxy/internal/cadence/error"
 )
@@ -39,6 +41,9 @@ type (
 		IProxyMessage
 		GetError() error
 		SetError(value error)
+		GetErrors() []error
+		AppendError(value error)
+		Unwrap() error
 	}
 )
 
@@ -58,32 +63,115 @@ func NewProxyReply() *ProxyReply {
 // -------------------------------------------------------------------------
 // IProxyReply interface methods for implementing the IProxyReply interface
 
-// GetError gets the CadenceError encoded as a JSON string in a ProxyReply's
-// Properties map
+// GetError gets the error chain encoded in a ProxyReply's Errors property,
+// reconstructed as a chain of *CodedError values whose Is/As/Unwrap
+// semantics match a chain built locally with fmt.Errorf("...: %w", ...), so
+// callers can do errors.Is(reply.GetError(), someSentinel).  Replies
+// written before the Errors property existed only carry the legacy Error
+// property, so GetError falls back to that as a single-frame chain.
 //
-// returns proxyerror.CadenceError -> a CadenceError struct encoded with the
-// JSON property values at a ProxyReply's Error property
+// returns error -> the head of the reconstructed error chain, or nil if the
+// reply carries no error
 func (reply *ProxyReply) GetError() error {
+	var frames []errorFrame
+	if err := reply.GetJSONProperty("Errors", &frames); err == nil && len(frames) > 0 {
+		return chainFrames(frames)
+	}
+
 	var cadenceError proxyerror.CadenceError
-	err := reply.GetJSONProperty("Error", &cadenceError)
-	if err != nil {
+	if err := reply.GetJSONProperty("Error", &cadenceError); err != nil {
 		return nil
 	}
 
-	if &cadenceError != nil {
-		err = cadenceError.ToError()
+	return cadenceError.ToError()
+}
+
+// GetErrors gets the flattened error chain encoded in a ProxyReply's Errors
+// property as a slice of *CodedError, outermost frame first.  It falls back
+// to the legacy single-frame Error property the same way GetError does.
+//
+// returns []error -> the reply's error chain, outermost frame first, or nil
+// if the reply carries no error
+func (reply *ProxyReply) GetErrors() []error {
+	var frames []errorFrame
+	if err := reply.GetJSONProperty("Errors", &frames); err != nil || len(frames) == 0 {
+		if head := reply.GetError(); head != nil {
+			return []error{head}
+		}
+
+		return nil
+	}
+
+	errs := make([]error, len(frames))
+	for i, f := range frames {
+		errs[i] = &CodedError{Type: f.Type, Message: f.Message, Code: f.Code}
 	}
 
-	return err
+	return errs
 }
 
-// SetError sets a CadenceError as a JSON string in a ProxyReply's
-// properties map at the Error Property
+// SetError flattens value's error chain - following both the single-cause
+// Unwrap() error convention and the errors.Join multi-cause
+// Unwrap() []error convention - and stores it as a ProxyReply's Errors
+// property.  It also populates the legacy Error property with just the
+// head frame, so a reply built against this version of the package is
+// still readable by a peer that only understands the old single-error
+// wire format.
 //
-// param proxyerror.CadenceError -> the CadenceError to marshal into a
-// JSON string and set at a ProxyReply's Error property
+// param value error -> the error, optionally wrapping others, to marshal
+// into a ProxyReply's Errors and Error properties
 func (reply *ProxyReply) SetError(value error) {
-	reply.SetJSONProperty("Error", proxyerror.NewCadenceError(value))
+	if value == nil {
+		reply.SetJSONProperty("Errors", nil)
+		reply.SetJSONProperty("Error", nil)
+
+		return
+	}
+
+	frames := flattenChain(value)
+	reply.SetJSONProperty("Errors", frames)
+	reply.SetJSONProperty("Error", proxyerror.NewCadenceError(&CodedError{
+		Type:    frames[0].Type,
+		Message: frames[0].Message,
+		Code:    frames[0].Code,
+	}))
+}
+
+// AppendError appends value's error chain to the end of a ProxyReply's
+// existing error chain, rather than replacing it.  It's used when a later
+// stage of a reply's processing wants to add context (e.g. a cleanup
+// failure) without discarding an error a prior stage already recorded.
+//
+// param value error -> the error, optionally wrapping others, to append
+func (reply *ProxyReply) AppendError(value error) {
+	if value == nil {
+		return
+	}
+
+	existing := reply.GetErrors()
+	frames := make([]errorFrame, 0, len(existing)+1)
+	for _, err := range existing {
+		frames = append(frames, classify(err))
+	}
+
+	frames = append(frames, flattenChain(value)...)
+
+	reply.SetJSONProperty("Errors", frames)
+	reply.SetJSONProperty("Error", proxyerror.NewCadenceError(&CodedError{
+		Type:    frames[0].Type,
+		Message: frames[0].Message,
+		Code:    frames[0].Code,
+	}))
+}
+
+// Unwrap returns the cause of the reply's head error, matching the
+// conventional errors.Unwrap(reply) shape so a ProxyReply can be passed
+// directly to errors.Is/errors.As.
+//
+// returns error -> the cause of the reply's head error, or nil if the
+// reply carries no error or its head error wraps nothing
+func (reply *ProxyReply) Unwrap() error {
+	return errors.Unwrap(reply.GetError())
 }
 
 // -------------------------------------------------------------------------
@@ -102,6 +190,16 @@ func (reply *ProxyReply) Clone() IProxyMessage {
 func (reply *ProxyReply) CopyTo(target IProxyMessage) {
 	reply.ProxyMessage.CopyTo(target)
 	if v, ok := target.(IProxyReply); ok {
-		v.SetError(reply.GetError())
+		errs := reply.GetErrors()
+		if len(errs) == 0 {
+			v.SetError(nil)
+
+			return
+		}
+
+		v.SetError(errs[0])
+		for _, err := range errs[1:] {
+			v.AppendError(err)
+		}
 	}
 }
@@ -0,0 +1,146 @@
+//-----------------------------------------------------------------------------
+// FILE:		schema_registry.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import "sync"
+
+type (
+
+	// paramSchemaPair is the ArgSchema/ResultSchema most recently accepted
+	// for a registered activity or workflow.
+	paramSchemaPair struct {
+		argSchema    []ParamSchema
+		resultSchema []ParamSchema
+	}
+
+	// SchemaRegistry is where registration handlers record the
+	// ArgSchema/ResultSchema an ActivityRegisterRequest or
+	// WorkflowRegisterRequest declares, and where the invoke path looks
+	// them back up: Register* rejects an incompatible re-registration via
+	// CheckSchemaEvolution before recording it, and ValidateArgs checks an
+	// invocation's payload against whatever was last recorded.  The zero
+	// value is not ready to use; construct one with NewSchemaRegistry.
+	SchemaRegistry struct {
+		mu         sync.RWMutex
+		activities map[string]paramSchemaPair
+		workflows  map[string]paramSchemaPair
+	}
+)
+
+// NewSchemaRegistry is the default constructor for a SchemaRegistry.
+//
+// returns *SchemaRegistry -> a pointer to a newly initialized
+// SchemaRegistry in memory
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		activities: make(map[string]paramSchemaPair),
+		workflows:  make(map[string]paramSchemaPair),
+	}
+}
+
+// RegisterActivity checks request's ArgSchema/ResultSchema for
+// compatibility with whatever is already registered for an activity named
+// request.GetName(), via CheckSchemaEvolution, and records it as the
+// activity's current schema if it's accepted.
+//
+// returns error -> the error CheckSchemaEvolution rejected the request
+// with, or nil if it was accepted and recorded
+func (r *SchemaRegistry) RegisterActivity(request *ActivityRegisterRequest) error {
+	name := stringOrEmpty(request.GetName())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prior := r.activities[name]
+	if err := request.CheckSchemaEvolution(prior.argSchema, prior.resultSchema); err != nil {
+		return err
+	}
+
+	r.activities[name] = paramSchemaPair{
+		argSchema:    request.GetArgSchema(),
+		resultSchema: request.GetResultSchema(),
+	}
+
+	return nil
+}
+
+// RegisterWorkflow checks request's ArgSchema/ResultSchema for
+// compatibility with whatever is already registered for a workflow named
+// request.GetName(), via CheckSchemaEvolution, and records it as the
+// workflow's current schema if it's accepted.
+//
+// returns error -> the error CheckSchemaEvolution rejected the request
+// with, or nil if it was accepted and recorded
+func (r *SchemaRegistry) RegisterWorkflow(request *WorkflowRegisterRequest) error {
+	name := stringOrEmpty(request.GetName())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prior := r.workflows[name]
+	if err := request.CheckSchemaEvolution(prior.argSchema, prior.resultSchema); err != nil {
+		return err
+	}
+
+	r.workflows[name] = paramSchemaPair{
+		argSchema:    request.GetArgSchema(),
+		resultSchema: request.GetResultSchema(),
+	}
+
+	return nil
+}
+
+// ValidateActivityArgs validates args - an invocation's raw, positional
+// argument payloads - against the ArgSchema currently registered for the
+// activity named name, before the invoke handler dispatches args to user
+// code.
+//
+// returns error -> a *CodedError with code ErrorCodeArgumentMismatch, or
+// nil if name has no registered schema or args satisfies it
+func (r *SchemaRegistry) ValidateActivityArgs(name string, args [][]byte) error {
+	r.mu.RLock()
+	schema := r.activities[name].argSchema
+	r.mu.RUnlock()
+
+	return ValidateArgs(schema, args)
+}
+
+// ValidateWorkflowArgs validates args - an invocation's raw, positional
+// argument payloads - against the ArgSchema currently registered for the
+// workflow named name, before the invoke handler dispatches args to user
+// code.
+//
+// returns error -> a *CodedError with code ErrorCodeArgumentMismatch, or
+// nil if name has no registered schema or args satisfies it
+func (r *SchemaRegistry) ValidateWorkflowArgs(name string, args [][]byte) error {
+	r.mu.RLock()
+	schema := r.workflows[name].argSchema
+	r.mu.RUnlock()
+
+	return ValidateArgs(schema, args)
+}
+
+// stringOrEmpty dereferences value, treating a nil *string the same as an
+// empty one, for use as a registry lookup key.
+func stringOrEmpty(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
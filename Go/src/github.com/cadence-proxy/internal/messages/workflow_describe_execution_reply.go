@@ -21,6 +21,11 @@ import (
 	cadenceshared "go.uber.org/cadence/.gen/go/shared"
 
 	internal "github.com/cadence-proxy/internal"
+	"github.com/cadence-proxy/internal/backend"
+	cadencebackend "github.com/cadence-proxy/internal/backend/cadence"
+	temporalbackend "github.com/cadence-proxy/internal/backend/temporal"
+
+	"go.temporal.io/api/workflowservice/v1"
 )
 
 type (
@@ -47,25 +52,69 @@ func NewWorkflowDescribeExecutionReply() *WorkflowDescribeExecutionReply {
 }
 
 // GetDetails gets the WorkflowDescribeExecutionReply's Details property from its
+// properties map, the workflow execution details.  The concrete response
+// type depends on the active backend.Current(), but it is always decoded
+// from the same "Details" JSON property slot so the wire format is stable
+// across backends.
+//
+// returns backend.DescribeWorkflowExecutionResponse -> the workflow
+// execution details.
+func (reply *WorkflowDescribeExecutionReply) GetDetails() backend.DescribeWorkflowExecutionResponse {
+	switch backend.Current() {
+	case backend.Temporal:
+		resp := new(workflowservice.DescribeWorkflowExecutionResponse)
+		if err := reply.GetJSONProperty("Details", resp); err != nil {
+			return nil
+		}
+
+		return temporalbackend.NewDescribeWorkflowExecutionResponse(resp)
+	default:
+		resp := new(cadenceshared.DescribeWorkflowExecutionResponse)
+		if err := reply.GetJSONProperty("Details", resp); err != nil {
+			return nil
+		}
+
+		return cadencebackend.NewDescribeWorkflowExecutionResponse(resp)
+	}
+}
+
+// SetDetails sets the WorkflowDescribeExecutionReply's Details property in its
 // properties map, the workflow execution details.
 //
-// returns *workflow.DescribeWorkflowExecutionResponse -> the *cadenceshared.DescribeWorkflowExecutionResponse.
-func (reply *WorkflowDescribeExecutionReply) GetDetails() *cadenceshared.DescribeWorkflowExecutionResponse {
-	resp := new(cadenceshared.DescribeWorkflowExecutionResponse)
-	err := reply.GetJSONProperty("Details", resp)
-	if err != nil {
+// param value backend.DescribeWorkflowExecutionResponse -> the workflow
+// execution details.
+func (reply *WorkflowDescribeExecutionReply) SetDetails(value backend.DescribeWorkflowExecutionResponse) {
+	if value == nil {
+		reply.SetJSONProperty("Details", nil)
+		return
+	}
+
+	reply.SetJSONProperty("Details", value.Unwrap())
+}
+
+// GetHeaders gets the WorkflowDescribeExecutionReply's Headers property from
+// its properties map: the gRPC/Thrift response metadata (server version,
+// shard, tracing/baggage, ...) forwarded back from the describe workflow
+// execution call, filtered through the configured headers.Config.
+//
+// returns map[string][]string -> the forwarded response headers, or nil if
+// none were set.
+func (reply *WorkflowDescribeExecutionReply) GetHeaders() map[string][]string {
+	var result map[string][]string
+	if err := reply.GetJSONProperty("Headers", &result); err != nil {
 		return nil
 	}
 
-	return resp
+	return result
 }
 
-// SetDetails sets the WorkflowDescribeExecutionReply's Details property in its
-// properties map, the workflow execution details.
+// SetHeaders sets the WorkflowDescribeExecutionReply's Headers property in
+// its properties map: the gRPC/Thrift response metadata forwarded back from
+// the describe workflow execution call.
 //
-// param value *workflow.DescribeWorkflowExecutionResponse -> the *cadenceshared.DescribeWorkflowExecutionResponse.
-func (reply *WorkflowDescribeExecutionReply) SetDetails(value *cadenceshared.DescribeWorkflowExecutionResponse) {
-	reply.SetJSONProperty("Details", value)
+// param value map[string][]string -> the response headers to forward.
+func (reply *WorkflowDescribeExecutionReply) SetHeaders(value map[string][]string) {
+	reply.SetJSONProperty("Headers", value)
 }
 
 // -------------------------------------------------------------------------
@@ -85,5 +134,6 @@ func (reply *WorkflowDescribeExecutionReply) CopyTo(target IProxyMessage) {
 	reply.WorkflowReply.CopyTo(target)
 	if v, ok := target.(*WorkflowDescribeExecutionReply); ok {
 		v.SetDetails(reply.GetDetails())
+		v.SetHeaders(reply.GetHeaders())
 	}
 }
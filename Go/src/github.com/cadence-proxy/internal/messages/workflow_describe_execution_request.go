@@ -0,0 +1,120 @@
+//-----------------------------------------------------------------------------
+// FILE:		workflow_describe_execution_request.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"context"
+
+	internal "github.com/cadence-proxy/internal"
+	"github.com/cadence-proxy/internal/headers"
+)
+
+type (
+
+	// WorkflowDescribeExecutionRequest is a WorkflowRequest of MessageType
+	// WorkflowDescribeExecutionRequest.  It holds a reference to a
+	// WorkflowRequest in memory and is the request type for a
+	// WorkflowDescribeExecutionReply.
+	WorkflowDescribeExecutionRequest struct {
+		*WorkflowRequest
+	}
+)
+
+// NewWorkflowDescribeExecutionRequest is the constructor for
+// a WorkflowDescribeExecutionRequest.
+//
+// returns *WorkflowDescribeExecutionRequest -> a pointer to a newly
+// initialized WorkflowDescribeExecutionRequest.
+func NewWorkflowDescribeExecutionRequest() *WorkflowDescribeExecutionRequest {
+	request := new(WorkflowDescribeExecutionRequest)
+	request.WorkflowRequest = NewWorkflowRequest()
+	request.SetType(internal.WorkflowDescribeExecutionRequest)
+	request.SetReplyType(internal.WorkflowDescribeExecutionReply)
+
+	return request
+}
+
+// GetHeaders gets the WorkflowDescribeExecutionRequest's Headers property
+// from its properties map: the gRPC/Thrift metadata extracted from the
+// inbound describe workflow execution call, filtered through the
+// configured headers.Config, to be propagated onto the outbound backend
+// call.
+//
+// returns map[string][]string -> the headers to forward, or nil if none
+// were set.
+func (request *WorkflowDescribeExecutionRequest) GetHeaders() map[string][]string {
+	var result map[string][]string
+	if err := request.GetJSONProperty("Headers", &result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// SetHeaders sets the WorkflowDescribeExecutionRequest's Headers property
+// in its properties map: the gRPC/Thrift metadata extracted from the
+// inbound describe workflow execution call, to be propagated onto the
+// outbound backend call.
+//
+// param value map[string][]string -> the headers to forward.
+func (request *WorkflowDescribeExecutionRequest) SetHeaders(value map[string][]string) {
+	request.SetJSONProperty("Headers", value)
+}
+
+// SetHeadersFromIncomingContext sets a WorkflowDescribeExecutionRequest's
+// Headers property from ctx's inbound gRPC metadata, filtered through
+// cfg's allow/deny lists.  Called when this request arrives over gRPC,
+// before it is dispatched to the backend.
+func (request *WorkflowDescribeExecutionRequest) SetHeadersFromIncomingContext(ctx context.Context, cfg headers.Config) {
+	request.SetHeaders(headers.FromIncomingContext(ctx, cfg))
+}
+
+// SetHeadersFromThrift sets a WorkflowDescribeExecutionRequest's Headers
+// property from a Thrift request's string header map, filtered through
+// cfg's allow/deny lists.  Called when this request arrives over Cadence's
+// Thrift transport, before it is dispatched to the backend.
+func (request *WorkflowDescribeExecutionRequest) SetHeadersFromThrift(thriftHeaders map[string]string, cfg headers.Config) {
+	request.SetHeaders(headers.FromThrift(thriftHeaders, cfg))
+}
+
+// OutgoingContext returns ctx with this WorkflowDescribeExecutionRequest's
+// Headers attached to its outgoing gRPC metadata, so they propagate to the
+// backend's DescribeWorkflowExecution call.
+func (request *WorkflowDescribeExecutionRequest) OutgoingContext(ctx context.Context) context.Context {
+	return headers.ToOutgoingContext(ctx, request.GetHeaders())
+}
+
+// -------------------------------------------------------------------------
+// IProxyMessage interface methods for implementing the IProxyMessage interface
+
+// Clone inherits docs from WorkflowRequest.Clone()
+func (request *WorkflowDescribeExecutionRequest) Clone() IProxyMessage {
+	workflowDescribeExecutionRequest := NewWorkflowDescribeExecutionRequest()
+	var messageClone IProxyMessage = workflowDescribeExecutionRequest
+	request.CopyTo(messageClone)
+
+	return messageClone
+}
+
+// CopyTo inherits docs from WorkflowRequest.CopyTo()
+func (request *WorkflowDescribeExecutionRequest) CopyTo(target IProxyMessage) {
+	request.WorkflowRequest.CopyTo(target)
+	if v, ok := target.(*WorkflowDescribeExecutionRequest); ok {
+		v.SetHeaders(request.GetHeaders())
+	}
+}
@@ -0,0 +1,225 @@
+//-----------------------------------------------------------------------------
+// FILE:		workflow_register_request.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package messages
+
+import (
+	"fmt"
+
+	internal "github.com/cadence-proxy/internal"
+)
+
+type (
+
+	// WorkflowRegisterRequest is a WorkflowRequest of MessageType
+	// WorkflowRegisterRequest.
+	//
+	// A WorkflowRegisterRequest contains a reference to a
+	// WorkflowRequest struct in memory and ReplyType, which is
+	// the corresponding MessageType for replying to this WorkflowRequest
+	//
+	// Registers a workflow with the cadence server
+	WorkflowRegisterRequest struct {
+		*WorkflowRequest
+	}
+)
+
+// NewWorkflowRegisterRequest is the default constructor for a WorkflowRegisterRequest
+//
+// returns *WorkflowRegisterRequest -> a pointer to a newly initialized WorkflowRegisterRequest
+// in memory
+func NewWorkflowRegisterRequest() *WorkflowRegisterRequest {
+	request := new(WorkflowRegisterRequest)
+	request.WorkflowRequest = NewWorkflowRequest()
+	request.SetType(internal.WorkflowRegisterRequest)
+	request.SetReplyType(internal.WorkflowRegisterReply)
+
+	return request
+}
+
+// GetName gets a WorkflowRegisterRequest's Name field
+// from its properties map.  Specifies the name of the workflow to
+// be registered.
+//
+// returns *string -> *string representing the name of the
+// workflow to be registered
+func (request *WorkflowRegisterRequest) GetName() *string {
+	return request.GetStringProperty("Name")
+}
+
+// SetName sets a WorkflowRegisterRequest's Name field
+// from its properties map.  Specifies the name of the workflow to
+// be registered.
+//
+// param value *string -> *string representing the name of the
+// workflow to be registered
+func (request *WorkflowRegisterRequest) SetName(value *string) {
+	request.SetStringProperty("Name", value)
+}
+
+// GetDomain gets a WorkflowRegisterRequest's Domain value
+// from its properties map
+//
+// returns *string -> pointer to a string in memory holding the value
+// of a WorkflowRegisterRequest's Domain
+func (request *WorkflowRegisterRequest) GetDomain() *string {
+	return request.GetStringProperty("Domain")
+}
+
+// SetDomain sets a WorkflowRegisterRequest's Domain value
+// in its properties map.
+//
+// param value *string -> a pointer to a string in memory that holds the value
+// to be set in the properties map
+func (request *WorkflowRegisterRequest) SetDomain(value *string) {
+	request.SetStringProperty("Domain", value)
+}
+
+// GetArgSchema gets a WorkflowRegisterRequest's ArgSchema field from its
+// properties map.  Describes the workflow's positional arguments, letting
+// a cross-language caller discover its signature at runtime instead of
+// hand-syncing it with the Go implementation.
+//
+// returns []ParamSchema -> the workflow's argument schema, or nil if none
+// is declared
+func (request *WorkflowRegisterRequest) GetArgSchema() []ParamSchema {
+	var schema []ParamSchema
+	if err := request.GetJSONProperty("ArgSchema", &schema); err != nil {
+		return nil
+	}
+
+	return schema
+}
+
+// SetArgSchema sets a WorkflowRegisterRequest's ArgSchema field in its
+// properties map.  Describes the workflow's positional arguments, letting
+// a cross-language caller discover its signature at runtime instead of
+// hand-syncing it with the Go implementation.
+//
+// param value []ParamSchema -> the workflow's argument schema, or nil to
+// leave it undeclared
+func (request *WorkflowRegisterRequest) SetArgSchema(value []ParamSchema) {
+	request.SetJSONProperty("ArgSchema", value)
+}
+
+// GetResultSchema gets a WorkflowRegisterRequest's ResultSchema field from
+// its properties map.  Describes the workflow's return value the same way
+// ArgSchema describes its arguments.
+//
+// returns []ParamSchema -> the workflow's result schema, or nil if none is
+// declared
+func (request *WorkflowRegisterRequest) GetResultSchema() []ParamSchema {
+	var schema []ParamSchema
+	if err := request.GetJSONProperty("ResultSchema", &schema); err != nil {
+		return nil
+	}
+
+	return schema
+}
+
+// SetResultSchema sets a WorkflowRegisterRequest's ResultSchema field in
+// its properties map.  Describes the workflow's return value the same way
+// ArgSchema describes its arguments.
+//
+// param value []ParamSchema -> the workflow's result schema, or nil to
+// leave it undeclared
+func (request *WorkflowRegisterRequest) SetResultSchema(value []ParamSchema) {
+	request.SetJSONProperty("ResultSchema", value)
+}
+
+// GetAllowSchemaEvolution gets a WorkflowRegisterRequest's
+// AllowSchemaEvolution field from its properties map.  When true, a
+// re-registration of this workflow is accepted even if its ArgSchema or
+// ResultSchema is incompatible with the version already registered; when
+// false (the default), an incompatible re-registration is rejected.
+//
+// returns bool -> true if an incompatible schema change should be allowed
+func (request *WorkflowRegisterRequest) GetAllowSchemaEvolution() bool {
+	return request.GetBoolProperty("AllowSchemaEvolution")
+}
+
+// SetAllowSchemaEvolution sets a WorkflowRegisterRequest's
+// AllowSchemaEvolution field in its properties map.  When true, a
+// re-registration of this workflow is accepted even if its ArgSchema or
+// ResultSchema is incompatible with the version already registered; when
+// false (the default), an incompatible re-registration is rejected.
+//
+// param value bool -> true if an incompatible schema change should be
+// allowed
+func (request *WorkflowRegisterRequest) SetAllowSchemaEvolution(value bool) {
+	request.SetBoolProperty("AllowSchemaEvolution", value)
+}
+
+// CheckSchemaEvolution reports the error the registration handler should
+// reject this request with, given the ArgSchema and ResultSchema already
+// registered for a workflow of this Name (prior is nil, nil if this is the
+// first registration).  It returns nil if the request should be accepted:
+// either because there is no prior registration, request's ArgSchema and
+// ResultSchema are both IsSchemaCompatible with it, or the request sets
+// AllowSchemaEvolution.
+//
+// returns error -> a *CodedError with code ErrorCodeArgumentMismatch
+// naming the incompatible property, or nil if re-registration is allowed
+func (request *WorkflowRegisterRequest) CheckSchemaEvolution(priorArgSchema, priorResultSchema []ParamSchema) error {
+	if request.GetAllowSchemaEvolution() {
+		return nil
+	}
+
+	name := stringOrEmpty(request.GetName())
+
+	if !IsSchemaCompatible(priorArgSchema, request.GetArgSchema()) {
+		return &CodedError{
+			Type:    "ArgumentMismatch",
+			Message: fmt.Sprintf("workflow %q: ArgSchema is incompatible with the already-registered schema", name),
+			Code:    ErrorCodeArgumentMismatch,
+		}
+	}
+
+	if !IsSchemaCompatible(priorResultSchema, request.GetResultSchema()) {
+		return &CodedError{
+			Type:    "ArgumentMismatch",
+			Message: fmt.Sprintf("workflow %q: ResultSchema is incompatible with the already-registered schema", name),
+			Code:    ErrorCodeArgumentMismatch,
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------------
+// IProxyMessage interface methods for implementing the IProxyMessage interface
+
+// Clone inherits docs from WorkflowRequest.Clone()
+func (request *WorkflowRegisterRequest) Clone() IProxyMessage {
+	workflowRegisterRequest := NewWorkflowRegisterRequest()
+	var messageClone IProxyMessage = workflowRegisterRequest
+	request.CopyTo(messageClone)
+
+	return messageClone
+}
+
+// CopyTo inherits docs from WorkflowRequest.CopyTo()
+func (request *WorkflowRegisterRequest) CopyTo(target IProxyMessage) {
+	request.WorkflowRequest.CopyTo(target)
+	if v, ok := target.(*WorkflowRegisterRequest); ok {
+		v.SetName(request.GetName())
+		v.SetDomain(request.GetDomain())
+		v.SetArgSchema(request.GetArgSchema())
+		v.SetResultSchema(request.GetResultSchema())
+		v.SetAllowSchemaEvolution(request.GetAllowSchemaEvolution())
+	}
+}
@@ -0,0 +1,179 @@
+//-----------------------------------------------------------------------------
+// FILE:		valuebag.go
+// CONTRIBUTOR: John C Burns
+// COPYRIGHT:	Copyright (c) 2016-2019 by neonFORGE, LLC.  All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package valuebag implements a layered key/value bag that middleware
+// (logging, tracing, metrics, auth) can attach to a workflow or activity
+// Context, independent of the backend's own workflow.Context value
+// propagation.  It is modeled on the getlantern/context design: a Bag
+// pushed onto the calling goroutine via Enter is visible to Current() from
+// any code running on that same goroutine until the matching Exit, which
+// lets values set on a proxy Context automatically show up in every log
+// line emitted while that Context is active.
+package valuebag
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// Bag is a layered, thread-safe key/value map.  A Bag created via NewChild
+// inherits its parent's values but can shadow them with its own, without
+// mutating the parent - the pattern a child workflow uses to inherit and
+// then override values set by its parent.
+type Bag struct {
+	mu     sync.RWMutex
+	parent *Bag
+	values map[interface{}]interface{}
+}
+
+// New creates an empty, top-level Bag.
+func New() *Bag {
+	return &Bag{values: make(map[interface{}]interface{})}
+}
+
+// NewChild creates a Bag layered on top of b: lookups that miss in the
+// child fall through to the parent, but writes to the child never affect b.
+func (b *Bag) NewChild() *Bag {
+	return &Bag{parent: b, values: make(map[interface{}]interface{})}
+}
+
+// WithValue attaches value under key, shadowing any value of the same key
+// in a parent Bag.
+func (b *Bag) WithValue(key, value interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.values[key] = value
+}
+
+// Value returns the value attached to key, checking this Bag and then
+// walking up through parent Bags.  It returns nil if key isn't set anywhere
+// in the chain.
+func (b *Bag) Value(key interface{}) interface{} {
+	value, _ := b.Lookup(key)
+	return value
+}
+
+// Lookup returns the value attached to key and true, checking this Bag and
+// then walking up through parent Bags, or nil and false if key isn't set
+// anywhere in the chain.  Callers that need to distinguish "not set" from a
+// legitimately stored nil - e.g. a Context.Value deciding whether to fall
+// back to some other Bag - should use Lookup rather than Value.
+func (b *Bag) Lookup(key interface{}) (interface{}, bool) {
+	b.mu.RLock()
+	value, ok := b.values[key]
+	parent := b.parent
+	b.mu.RUnlock()
+	if ok {
+		return value, true
+	}
+
+	if parent != nil {
+		return parent.Lookup(key)
+	}
+
+	return nil, false
+}
+
+// registry maps a goroutine id to the stack of Bags currently active on
+// that goroutine.  Enter/Exit push and pop this stack so that any function
+// running on a goroutine participating in a unit of work - not just the one
+// that called Enter - can read the active Bag via Current.
+var (
+	registryMu sync.Mutex
+	registry   = make(map[int64][]*Bag)
+)
+
+// Enter pushes b onto the calling goroutine's active-Bag stack, making it
+// (and anything it inherits from a parent) visible to Current() for any code
+// that runs on this goroutine until the matching Exit.
+func Enter(b *Bag) {
+	gid := goroutineID()
+	registryMu.Lock()
+	registry[gid] = append(registry[gid], b)
+	registryMu.Unlock()
+}
+
+// Exit pops the most recently Entered Bag off the calling goroutine's
+// active-Bag stack.  Exit must be called once for every Enter, typically via
+// defer immediately after Enter.
+func Exit() {
+	gid := goroutineID()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	stack := registry[gid]
+	if len(stack) == 0 {
+		return
+	}
+
+	if len(stack) == 1 {
+		delete(registry, gid)
+		return
+	}
+
+	registry[gid] = stack[:len(stack)-1]
+}
+
+// Current returns the Bag most recently Entered on the calling goroutine, or
+// nil if none is active.
+func Current() *Bag {
+	gid := goroutineID()
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	stack := registry[gid]
+	if len(stack) == 0 {
+		return nil
+	}
+
+	return stack[len(stack)-1]
+}
+
+// Value returns the value attached to key in the Bag most recently Entered
+// on the calling goroutine, or nil if no Bag is active or key isn't set
+// anywhere in its chain.  This is the hook middleware without direct access
+// to a Context (e.g. a log line formatter several stack frames away) uses
+// to read the ambient values attached by Enter, rather than calling
+// Current().Value(key) itself.
+func Value(key interface{}) interface{} {
+	current := Current()
+	if current == nil {
+		return nil
+	}
+
+	return current.Value(key)
+}
+
+// goroutineID parses the numeric goroutine id out of the calling
+// goroutine's own stack trace header ("goroutine 123 [running]: ...").
+// There is no supported API for this in the standard library; it is only
+// used to key the Enter/Exit registry and never exposed to callers.
+func goroutineID() int64 {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	buf = bytes.TrimPrefix(buf, []byte("goroutine "))
+	if i := bytes.IndexByte(buf, ' '); i >= 0 {
+		buf = buf[:i]
+	}
+
+	id, err := strconv.ParseInt(string(buf), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}